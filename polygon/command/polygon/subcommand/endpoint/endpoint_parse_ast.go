@@ -445,11 +445,25 @@ func (s *Scanner) scanReturnStatement(ret *ast.ReturnStmt, variables map[string]
 		return
 	}
 
+	// Unwrap builder chain: response.Data(s, variable).WithCode(...)
+	successCall = s.unwrapResponseBuilderCall(successCall)
+
 	if !s.isResponseSuccessCall(successCall) {
 		return
 	}
 
-	// Extract the variable argument from response.Success()
+	// Explicit type argument: response.Data[Type](s, variable)
+	if indexExpr, ok := successCall.Fun.(*ast.IndexExpr); ok {
+		typeStr, _ := s.extractTypeString(indexExpr.Index)
+		endpoint.ReturnType = fmt.Sprintf("response.GenericResponse[%s]", typeStr)
+		return
+	}
+
+	if len(successCall.Args) < 2 {
+		return
+	}
+
+	// Extract the variable argument from response.Success() or response.Data()
 	successArg := successCall.Args[1] // response.Success(c, variable)
 	if successArg == nil {
 		return
@@ -586,8 +600,27 @@ func (s *Scanner) isCJSONCall(call *ast.CallExpr) bool {
 	return selExpr.Sel.Name == "JSON"
 }
 
+func (s *Scanner) unwrapResponseBuilderCall(call *ast.CallExpr) *ast.CallExpr {
+	for {
+		selExpr, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !strings.HasPrefix(selExpr.Sel.Name, "With") {
+			return call
+		}
+		inner, ok := selExpr.X.(*ast.CallExpr)
+		if !ok {
+			return call
+		}
+		call = inner
+	}
+}
+
 func (s *Scanner) isResponseSuccessCall(call *ast.CallExpr) bool {
-	selExpr, ok := call.Fun.(*ast.SelectorExpr)
+	fun := call.Fun
+	if indexExpr, ok := fun.(*ast.IndexExpr); ok {
+		fun = indexExpr.X
+	}
+
+	selExpr, ok := fun.(*ast.SelectorExpr)
 	if !ok {
 		return false
 	}
@@ -600,7 +633,7 @@ func (s *Scanner) isResponseSuccessCall(call *ast.CallExpr) bool {
 
 	for _, pattern := range responseSuccessPatterns {
 		if fullFuncName == pattern || strings.HasSuffix(fullFuncName, "."+pattern) {
-			return selExpr.Sel.Name == "Success"
+			return selExpr.Sel.Name == "Success" || selExpr.Sel.Name == "Data"
 		}
 	}
 	return false
@@ -0,0 +1,44 @@
+package endpoint_test
+
+import (
+	"testing"
+
+	"go.scnd.dev/open/polygon/command/polygon/subcommand/endpoint"
+)
+
+func TestParseResponseBuilder(t *testing.T) {
+	t.Chdir("testdata/builder")
+
+	result, err := endpoint.ParseAst(&endpoint.Config{
+		EndpointDir:  "endpoint",
+		EndpointFile: "endpoint/endpoint.go",
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse endpoints: %v", err)
+	}
+
+	returns := make(map[string]string)
+	for _, e := range result.Endpoints {
+		returns[e.Name] = e.ReturnType
+	}
+
+	cases := []struct {
+		handler  string
+		expected string
+	}{
+		{"HandleExplicit", "response.GenericResponse[*payload.User]"},
+		{"HandleInferred", "response.GenericResponse[User]"},
+		{"HandleChained", "response.GenericResponse[User]"},
+		{"HandleList", "response.GenericResponse[[]*User]"},
+	}
+	for _, c := range cases {
+		returnType, ok := returns[c.handler]
+		if !ok {
+			t.Errorf("Expected endpoint %s to be parsed", c.handler)
+			continue
+		}
+		if returnType != c.expected {
+			t.Errorf("Expected %s return type %s, got %s", c.handler, c.expected, returnType)
+		}
+	}
+}
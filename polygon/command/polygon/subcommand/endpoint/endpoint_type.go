@@ -101,5 +101,6 @@ var (
 	responseSuccessPatterns = []string{
 		"response.Success",
 		"response.SuccessResponse",
+		"response.Data",
 	}
 )
@@ -0,0 +1,8 @@
+package endpoint
+
+func Bind(api fiber.Router, handler *Handler) {
+	api.Get("/user/explicit", handler.HandleExplicit)
+	api.Get("/user/inferred", handler.HandleInferred)
+	api.Get("/user/chained", handler.HandleChained)
+	api.Get("/user/list", handler.HandleList)
+}
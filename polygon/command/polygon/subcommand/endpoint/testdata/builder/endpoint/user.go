@@ -0,0 +1,29 @@
+package endpoint
+
+type User struct {
+	Id   *uint64 `json:"id"`
+	Name *string `json:"name"`
+}
+
+func (r *Handler) HandleExplicit(c fiber.Ctx) error {
+	s := r.Span(c)
+	return c.JSON(response.Data[*payload.User](s, r.User()))
+}
+
+func (r *Handler) HandleInferred(c fiber.Ctx) error {
+	s := r.Span(c)
+	user := new(User)
+	return c.JSON(response.Data(s, user))
+}
+
+func (r *Handler) HandleChained(c fiber.Ctx) error {
+	s := r.Span(c)
+	user := &User{}
+	return c.JSON(response.Data(s, user).WithCode("user_found").WithMessage("found").WithPagination(nil))
+}
+
+func (r *Handler) HandleList(c fiber.Ctx) error {
+	s := r.Span(c)
+	users := make([]*User, 0)
+	return c.JSON(response.Data[[]*User](s, users).WithPagination(nil))
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/bsthun/gut"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.scnd.dev/open/polygon/package/span"
+	"golang.org/x/oauth2"
+)
+
+type Config struct {
+	Issuer          *string
+	ClientId        *string
+	ClientSecret    *string
+	RedirectUrl     *string
+	RedirectHosts   []string
+	Scopes          []string
+	StateStore      StateStore
+	StateDuration   *time.Duration
+	SessionSecret   []byte
+	SessionKeyset   *Keyset
+	SessionIssuer   *string
+	SessionAudience []string
+	SessionDuration *time.Duration
+	CookieName      *string
+	CookieDomain    *string
+	CookiePath      *string
+	CookieSecure    *bool
+}
+
+type Auth struct {
+	Config   *Config
+	Provider *oidc.Provider
+	Verifier *oidc.IDTokenVerifier
+	Oauth2   *oauth2.Config
+	States   StateStore
+	Keyset   *Keyset
+}
+
+func New(ctx context.Context, config *Config) (*Auth, error) {
+	if config.Issuer == nil || config.ClientId == nil || config.RedirectUrl == nil {
+		return nil, span.NewError(nil, "issuer, client id and redirect url are required", nil)
+	}
+
+	// * construct session keyset
+	keyset := config.SessionKeyset
+	if keyset == nil {
+		if len(config.SessionSecret) == 0 {
+			return nil, span.NewError(nil, "session secret or keyset is required", nil)
+		}
+		keyset = NewKeyset(NewHmacKey("session", config.SessionSecret))
+	}
+
+	// * discover provider metadata
+	provider, err := oidc.NewProvider(ctx, *config.Issuer)
+	if err != nil {
+		return nil, span.NewError(nil, "unable to discover oidc provider", err)
+	}
+
+	// * construct scopes
+	scopes := config.Scopes
+	if scopes == nil {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+
+	// * construct state store
+	states := config.StateStore
+	if states == nil {
+		states = NewMemoryStateStore()
+	}
+
+	return &Auth{
+		Config:   config,
+		Provider: provider,
+		Verifier: provider.Verifier(&oidc.Config{
+			ClientID: *config.ClientId,
+		}),
+		Oauth2: &oauth2.Config{
+			ClientID:     *config.ClientId,
+			ClientSecret: gut.Val(config.ClientSecret),
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  *config.RedirectUrl,
+			Scopes:       scopes,
+		},
+		States: states,
+		Keyset: keyset,
+	}, nil
+}
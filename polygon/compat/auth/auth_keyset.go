@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.scnd.dev/open/polygon/compat/predefine"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+type Key struct {
+	Id        *string
+	Method    jwt.SigningMethod
+	VerifyKey any
+	SignKey   any
+}
+
+type Keyset struct {
+	Keys []*Key
+}
+
+func NewHmacKey(id string, secret []byte) *Key {
+	return &Key{
+		Id:        &id,
+		Method:    jwt.SigningMethodHS256,
+		VerifyKey: secret,
+		SignKey:   secret,
+	}
+}
+
+func NewRsaKey(id string, public *rsa.PublicKey, private *rsa.PrivateKey) *Key {
+	key := &Key{
+		Id:        &id,
+		Method:    jwt.SigningMethodRS256,
+		VerifyKey: public,
+	}
+	if private != nil {
+		key.SignKey = private
+	}
+	return key
+}
+
+func NewEd25519Key(id string, public ed25519.PublicKey, private ed25519.PrivateKey) *Key {
+	key := &Key{
+		Id:        &id,
+		Method:    jwt.SigningMethodEdDSA,
+		VerifyKey: public,
+	}
+	if private != nil {
+		key.SignKey = private
+	}
+	return key
+}
+
+func NewKeyset(keys ...*Key) *Keyset {
+	return &Keyset{
+		Keys: keys,
+	}
+}
+
+func (r *Keyset) Methods() []string {
+	methods := make([]string, 0, len(r.Keys))
+	for _, key := range r.Keys {
+		methods = append(methods, key.Method.Alg())
+	}
+	return methods
+}
+
+func (r *Keyset) Lookup(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range r.Keys {
+		// * match key id when present
+		if kid != "" && (key.Id == nil || *key.Id != kid) {
+			continue
+		}
+
+		// * reject algorithm mismatch
+		if key.Method.Alg() != token.Method.Alg() {
+			continue
+		}
+
+		return key.VerifyKey, nil
+	}
+	return nil, errors.New("no matching verification key")
+}
+
+func (r *Keyset) Sign(claims jwt.Claims) (string, error) {
+	// * sign with first key holding a private part
+	for _, key := range r.Keys {
+		if key.SignKey == nil {
+			continue
+		}
+		token := jwt.NewWithClaims(key.Method, claims)
+		if key.Id != nil {
+			token.Header["kid"] = *key.Id
+		}
+		signed, err := token.SignedString(key.SignKey)
+		if err != nil {
+			return "", span.NewError(nil, "unable to sign token", err)
+		}
+		return signed, nil
+	}
+	return "", span.NewError(nil, "no signing key in keyset", nil)
+}
+
+func (r *Keyset) Parse(token string, options ...jwt.ParserOption) (*predefine.LoginClaims, error) {
+	options = append([]jwt.ParserOption{
+		jwt.WithValidMethods(r.Methods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}, options...)
+
+	claims := new(predefine.LoginClaims)
+	if _, err := jwt.ParseWithClaims(token, claims, r.Lookup, options...); err != nil {
+		return nil, span.NewError(nil, "invalid login token", errors.Join(span.ErrUnauthorized, err))
+	}
+	return claims, nil
+}
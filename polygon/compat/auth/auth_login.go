@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bsthun/gut"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.scnd.dev/open/polygon/compat/predefine"
+	"go.scnd.dev/open/polygon/package/span"
+	"golang.org/x/oauth2"
+)
+
+func (r *Auth) LoginUrl(ctx context.Context, redirect string) (string, error) {
+	if !r.AllowedRedirect(redirect) {
+		return "", span.NewError(nil, "redirect target is not allowed", nil)
+	}
+
+	key, err := Random()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := Random()
+	if err != nil {
+		return "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	// * save state
+	expiredAt := time.Now().Add(gut.Val(r.Config.StateDuration, 10*time.Minute))
+	if err := r.States.Save(ctx, key, &State{
+		Verifier:  &verifier,
+		Nonce:     &nonce,
+		Redirect:  &redirect,
+		ExpiredAt: &expiredAt,
+	}); err != nil {
+		return "", span.NewError(nil, "unable to save login state", err)
+	}
+
+	return r.Oauth2.AuthCodeURL(key, oauth2.S256ChallengeOption(verifier), oidc.Nonce(nonce)), nil
+}
+
+func (r *Auth) Callback(ctx context.Context, key string, code string) (*predefine.OidcClaims, *State, error) {
+	// * consume state
+	state, err := r.States.Take(ctx, key)
+	if err != nil {
+		return nil, nil, span.NewError(nil, "unable to load login state", err)
+	}
+	if state == nil || (state.ExpiredAt != nil && time.Now().After(*state.ExpiredAt)) {
+		return nil, nil, span.NewError(nil, "invalid or expired login state", span.ErrUnauthorized)
+	}
+	if state.Redirect != nil && !r.AllowedRedirect(*state.Redirect) {
+		return nil, nil, span.NewError(nil, "redirect target is not allowed", span.ErrForbidden)
+	}
+
+	// * exchange authorization code
+	token, err := r.Oauth2.Exchange(ctx, code, oauth2.VerifierOption(*state.Verifier))
+	if err != nil {
+		return nil, nil, span.NewError(nil, "unable to exchange authorization code", err)
+	}
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, nil, span.NewError(nil, "missing id token in token response", span.ErrUnauthorized)
+	}
+
+	// * verify id token
+	idToken, err := r.Verifier.Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, nil, span.NewError(nil, "invalid id token", errors.Join(span.ErrUnauthorized, err))
+	}
+	if idToken.Nonce != *state.Nonce {
+		return nil, nil, span.NewError(nil, "id token nonce mismatch", span.ErrUnauthorized)
+	}
+
+	// * map claims
+	claims := new(predefine.OidcClaims)
+	if err := idToken.Claims(claims); err != nil {
+		return nil, nil, span.NewError(nil, "unable to parse id token claims", err)
+	}
+
+	return claims, state, nil
+}
+
+// AllowedRedirect accepts a same-origin path, or an absolute http(s) url whose
+// host is listed in Config.RedirectHosts.
+func (r *Auth) AllowedRedirect(redirect string) bool {
+	// * reject backslash and control characters browsers may normalize
+	if strings.ContainsFunc(redirect, func(c rune) bool { return c == '\\' || c < 0x20 || c == 0x7f }) {
+		return false
+	}
+	target, err := url.Parse(redirect)
+	if err != nil {
+		return false
+	}
+
+	// * allow relative path without scheme or authority
+	if target.Scheme == "" && target.Host == "" {
+		return strings.HasPrefix(redirect, "/") && !strings.HasPrefix(redirect, "//")
+	}
+
+	// * allow absolute url on configured host
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(target.Host)
+	return slices.ContainsFunc(r.Config.RedirectHosts, func(allowed string) bool {
+		allowed = strings.ToLower(allowed)
+		return allowed == host || allowed == strings.ToLower(target.Hostname())
+	})
+}
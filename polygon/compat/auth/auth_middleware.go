@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bsthun/gut"
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"go.scnd.dev/open/polygon/compat/predefine"
+	"go.scnd.dev/open/polygon/compat/response"
+	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry"
+)
+
+type MiddlewareConfig struct {
+	Keyset       *Keyset
+	Header       *string
+	Cookie       *string
+	Issuer       *string
+	Audience     []string
+	Leeway       *time.Duration
+	ErrorHandler fiber.ErrorHandler
+}
+
+type localClaimsKey struct{}
+
+func Middleware(config *MiddlewareConfig) fiber.Handler {
+	header := gut.Val(config.Header, fiber.HeaderAuthorization)
+	handler := config.ErrorHandler
+	if handler == nil {
+		handler = response.HandleError
+	}
+
+	// * construct parser options
+	options := []jwt.ParserOption{
+		jwt.WithLeeway(gut.Val(config.Leeway)),
+	}
+	if config.Issuer != nil {
+		options = append(options, jwt.WithIssuer(*config.Issuer))
+	}
+	if len(config.Audience) > 0 {
+		options = append(options, jwt.WithAudience(config.Audience...))
+	}
+
+	return func(c fiber.Ctx) error {
+		s := telemetry.SpanFromCtx(c)
+
+		// * extract and verify token
+		var claims *predefine.LoginClaims
+		var err error
+		if token := Token(c, header, config.Cookie); token == "" {
+			err = span.NewError(nil, "missing authentication token", span.ErrUnauthorized)
+		} else {
+			claims, err = config.Keyset.Parse(token, options...)
+		}
+		if err != nil {
+			if s != nil {
+				err = s.Error("authentication failed", err)
+			}
+			return handler(c, err)
+		}
+
+		// * attach claims
+		c.Locals(localClaimsKey{}, claims)
+		if s != nil {
+			subject, _ := claims.GetSubject()
+			s.Variable("auth.subject", subject)
+		}
+
+		return c.Next()
+	}
+}
+
+func Token(c fiber.Ctx, header string, cookie *string) string {
+	// * accept only the bearer scheme, other schemes fall through to cookie
+	if value := c.Get(header); len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+		if token := strings.TrimSpace(value[7:]); token != "" {
+			return token
+		}
+	}
+	if cookie != nil {
+		return c.Cookies(*cookie)
+	}
+	return ""
+}
+
+func ClaimsFromCtx(c fiber.Ctx) *predefine.LoginClaims {
+	claims, ok := c.Locals(localClaimsKey{}).(*predefine.LoginClaims)
+	if !ok {
+		return nil
+	}
+	return claims
+}
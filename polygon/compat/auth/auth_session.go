@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/bsthun/gut"
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"go.scnd.dev/open/polygon/compat/predefine"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+func (r *Auth) CookieName() string {
+	return gut.Val(r.Config.CookieName, "login")
+}
+
+func (r *Auth) Sign(userId uint64) (string, *predefine.LoginClaims, error) {
+	now := time.Now()
+	claims := &predefine.LoginClaims{
+		UserId:    &userId,
+		Issuer:    r.Config.SessionIssuer,
+		Audience:  r.Config.SessionAudience,
+		IssuedAt:  &now,
+		NotBefore: &now,
+		ExpiredAt: gut.Ptr(now.Add(gut.Val(r.Config.SessionDuration, 24*time.Hour))),
+	}
+
+	token, err := r.Keyset.Sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+func (r *Auth) Parse(token string) (*predefine.LoginClaims, error) {
+	return r.Keyset.Parse(token, r.ParserOptions()...)
+}
+
+func (r *Auth) ParserOptions() []jwt.ParserOption {
+	options := make([]jwt.ParserOption, 0)
+	if r.Config.SessionIssuer != nil {
+		options = append(options, jwt.WithIssuer(*r.Config.SessionIssuer))
+	}
+	if len(r.Config.SessionAudience) > 0 {
+		options = append(options, jwt.WithAudience(r.Config.SessionAudience...))
+	}
+	return options
+}
+
+func (r *Auth) Middleware() fiber.Handler {
+	return Middleware(&MiddlewareConfig{
+		Keyset:   r.Keyset,
+		Cookie:   gut.Ptr(r.CookieName()),
+		Issuer:   r.Config.SessionIssuer,
+		Audience: r.Config.SessionAudience,
+	})
+}
+
+func (r *Auth) Issue(c fiber.Ctx, userId uint64) (*predefine.LoginClaims, error) {
+	token, claims, err := r.Sign(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     r.CookieName(),
+		Value:    token,
+		Path:     gut.Val(r.Config.CookiePath, "/"),
+		Domain:   gut.Val(r.Config.CookieDomain),
+		Expires:  *claims.ExpiredAt,
+		Secure:   gut.Val(r.Config.CookieSecure, true),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return claims, nil
+}
+
+func (r *Auth) Verify(c fiber.Ctx) (*predefine.LoginClaims, error) {
+	token := c.Cookies(r.CookieName())
+	if token == "" {
+		return nil, span.NewError(nil, "missing login cookie", span.ErrUnauthorized)
+	}
+	return r.Parse(token)
+}
+
+func (r *Auth) Logout(c fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     r.CookieName(),
+		Value:    "",
+		Path:     gut.Val(r.Config.CookiePath, "/"),
+		Domain:   gut.Val(r.Config.CookieDomain),
+		Expires:  time.Unix(0, 0),
+		Secure:   gut.Val(r.Config.CookieSecure, true),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+type State struct {
+	Verifier  *string
+	Nonce     *string
+	Redirect  *string
+	ExpiredAt *time.Time
+}
+
+type StateStore interface {
+	Save(ctx context.Context, key string, state *State) error
+	Take(ctx context.Context, key string) (*State, error)
+}
+
+type MemoryStateStore struct {
+	Mutex  sync.Mutex
+	States map[string]*State
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		States: make(map[string]*State),
+	}
+}
+
+func (r *MemoryStateStore) Save(ctx context.Context, key string, state *State) error {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	// * purge expired states
+	now := time.Now()
+	for k, s := range r.States {
+		if s.ExpiredAt != nil && now.After(*s.ExpiredAt) {
+			delete(r.States, k)
+		}
+	}
+
+	r.States[key] = state
+	return nil
+}
+
+func (r *MemoryStateStore) Take(ctx context.Context, key string) (*State, error) {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	state, ok := r.States[key]
+	if !ok {
+		return nil, nil
+	}
+	delete(r.States, key)
+	return state, nil
+}
+
+func Random() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", span.NewError(nil, "unable to generate random value", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
@@ -0,0 +1,277 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bsthun/gut"
+	"github.com/gofiber/fiber/v3"
+	"go.scnd.dev/open/polygon/compat/auth"
+	"go.scnd.dev/open/polygon/compat/auth/authtest"
+	"go.scnd.dev/open/polygon/compat/predefine"
+	"go.scnd.dev/open/polygon/compat/response"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+func newAuth(t *testing.T) (*auth.Auth, *authtest.Provider) {
+	if err := gut.SetIdEncoderKey([]byte("polygon-test-key")); err != nil {
+		t.Fatalf("Failed to set id encoder key: %v", err)
+	}
+
+	provider, err := authtest.NewProvider("polygon", map[string]any{
+		"sub":                "user-1",
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Failed to start provider: %v", err)
+	}
+	t.Cleanup(provider.Close)
+
+	a, err := auth.New(context.Background(), &auth.Config{
+		Issuer:          gut.Ptr(provider.Issuer()),
+		ClientId:        gut.Ptr("polygon"),
+		ClientSecret:    gut.Ptr("secret"),
+		RedirectUrl:     gut.Ptr("http://localhost/callback"),
+		SessionSecret:   []byte("session-secret"),
+		SessionIssuer:   gut.Ptr("polygon"),
+		SessionAudience: []string{"polygon-web"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create auth: %v", err)
+	}
+	return a, provider
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	a, provider := newAuth(t)
+	ctx := context.Background()
+
+	url, err := a.LoginUrl(ctx, "/home")
+	if err != nil {
+		t.Fatalf("Failed to create login url: %v", err)
+	}
+	code, state, err := provider.Authorize(url)
+	if err != nil {
+		t.Fatalf("Failed to authorize: %v", err)
+	}
+
+	claims, loginState, err := a.Callback(ctx, state, code)
+	if err != nil {
+		t.Fatalf("Failed callback: %v", err)
+	}
+	if *claims.Id != "user-1" || *claims.Email != "alice@example.com" || *claims.Username != "alice" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+	if *loginState.Redirect != "/home" {
+		t.Errorf("Expected redirect /home, got %s", *loginState.Redirect)
+	}
+
+	// State is single use
+	_, _, err = a.Callback(ctx, state, code)
+	var spanError *span.Error
+	if !errors.As(err, &spanError) || spanError.Status() != http.StatusUnauthorized {
+		t.Errorf("Expected unauthorized replay error, got %v", err)
+	}
+}
+
+func TestLoginRedirect(t *testing.T) {
+	a, provider := newAuth(t)
+	a.Config.RedirectHosts = []string{"app.example.com"}
+	ctx := context.Background()
+
+	cases := []struct {
+		redirect string
+		allowed  bool
+	}{
+		{"/home", true},
+		{"/orders?page=2#top", true},
+		{"https://app.example.com/home", true},
+		{"https://APP.example.com:8443/home", true},
+		{"//evil.example", false},
+		{"https://evil.example", false},
+		{"http://evil.example/home", false},
+		{"/\\evil.example", false},
+		{"https://app.example.com@evil.example", false},
+		{"javascript:alert(1)", false},
+		{"home", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if allowed := a.AllowedRedirect(c.redirect); allowed != c.allowed {
+			t.Errorf("Expected redirect %q allowed %v, got %v", c.redirect, c.allowed, allowed)
+		}
+		if _, err := a.LoginUrl(ctx, c.redirect); (err == nil) != c.allowed {
+			t.Errorf("Expected login url for %q allowed %v, got %v", c.redirect, c.allowed, err)
+		}
+	}
+
+	// A tampered state is rejected on callback
+	url, err := a.LoginUrl(ctx, "/home")
+	if err != nil {
+		t.Fatalf("Failed to create login url: %v", err)
+	}
+	code, key, err := provider.Authorize(url)
+	if err != nil {
+		t.Fatalf("Failed to authorize: %v", err)
+	}
+	state, _ := a.States.Take(ctx, key)
+	state.Redirect = gut.Ptr("//evil.example")
+	_ = a.States.Save(ctx, key, state)
+	_, _, err = a.Callback(ctx, key, code)
+	var spanError *span.Error
+	if !errors.As(err, &spanError) || spanError.Status() != http.StatusForbidden {
+		t.Errorf("Expected forbidden redirect error, got %v", err)
+	}
+}
+
+func TestSessionCookie(t *testing.T) {
+	a, _ := newAuth(t)
+
+	app := fiber.New(fiber.Config{ErrorHandler: response.HandleError})
+	app.Post("/login", func(c fiber.Ctx) error {
+		_, err := a.Issue(c, 42)
+		return err
+	})
+	app.Get("/me", func(c fiber.Ctx) error {
+		claims, err := a.Verify(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(response.Data(nil, claims))
+	})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodPost, "/login", nil))
+	if err != nil {
+		t.Fatalf("Failed login request: %v", err)
+	}
+	cookies := res.Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("Expected one http only cookie, got %v", cookies)
+	}
+
+	// Valid cookie is accepted
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(cookies[0])
+	res, _ = app.Test(req)
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with cookie, got %d", res.StatusCode)
+	}
+
+	// Missing and tampered cookies are rejected
+	res, _ = app.Test(httptest.NewRequest(http.MethodGet, "/me", nil))
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without cookie, got %d", res.StatusCode)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: cookies[0].Name, Value: cookies[0].Value + "x"})
+	res, _ = app.Test(req)
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with tampered cookie, got %d", res.StatusCode)
+	}
+
+	// Tokens from a different issuer are rejected
+	other := *a
+	other.Config = &auth.Config{SessionSecret: a.Config.SessionSecret, SessionIssuer: gut.Ptr("other")}
+	token, _, _ := other.Sign(42)
+	if _, err := a.Parse(token); err == nil {
+		t.Errorf("Expected token from other issuer to be rejected")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	if err := gut.SetIdEncoderKey([]byte("polygon-test-key")); err != nil {
+		t.Fatalf("Failed to set id encoder key: %v", err)
+	}
+
+	rsaPrivate, _ := rsa.GenerateKey(rand.Reader, 2048)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	hmacKey := auth.NewHmacKey("hmac-2", []byte("current"))
+	rsaKey := auth.NewRsaKey("rsa-1", &rsaPrivate.PublicKey, rsaPrivate)
+	edKey := auth.NewEd25519Key("ed-1", edPublic, edPrivate)
+	retiredKey := auth.NewHmacKey("hmac-1", []byte("retired"))
+
+	keyset := auth.NewKeyset(hmacKey, rsaKey, edKey, retiredKey)
+	app := fiber.New()
+	app.Use(auth.Middleware(&auth.MiddlewareConfig{
+		Keyset:   keyset,
+		Cookie:   gut.Ptr("login"),
+		Issuer:   gut.Ptr("polygon"),
+		Audience: []string{"polygon-web"},
+	}))
+	app.Get("/me", func(c fiber.Ctx) error {
+		return c.SendString(gut.IdEncode(*auth.ClaimsFromCtx(c).UserId))
+	})
+
+	now := time.Now()
+	claims := func(modify func(claims *predefine.LoginClaims)) *predefine.LoginClaims {
+		c := &predefine.LoginClaims{
+			UserId:    gut.Ptr[uint64](42),
+			Issuer:    gut.Ptr("polygon"),
+			Audience:  []string{"polygon-web"},
+			IssuedAt:  gut.Ptr(now),
+			NotBefore: gut.Ptr(now),
+			ExpiredAt: gut.Ptr(now.Add(time.Hour)),
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+	sign := func(key *auth.Key, claims *predefine.LoginClaims) string {
+		token, err := auth.NewKeyset(key).Sign(claims)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return token
+	}
+	request := func(header string, cookie string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "login", Value: cookie})
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed request: %v", err)
+		}
+		return res.StatusCode
+	}
+
+	cases := []struct {
+		name     string
+		header   string
+		cookie   string
+		expected int
+	}{
+		{"hmac header", "Bearer " + sign(hmacKey, claims(nil)), "", 200},
+		{"retired hmac key", "Bearer " + sign(retiredKey, claims(nil)), "", 200},
+		{"rsa cookie", "", sign(rsaKey, claims(nil)), 200},
+		{"eddsa header", "bearer " + sign(edKey, claims(nil)), "", 200},
+		{"missing token", "", "", 401},
+		{"basic header with cookie", "Basic cG9seWdvbjpzZWNyZXQ=", sign(hmacKey, claims(nil)), 200},
+		{"unprefixed header", sign(hmacKey, claims(nil)), "", 401},
+		{"empty bearer with cookie", "Bearer  ", sign(hmacKey, claims(nil)), 200},
+		{"unknown key id", "Bearer " + sign(auth.NewHmacKey("hmac-3", []byte("current")), claims(nil)), "", 401},
+		{"wrong secret", "Bearer " + sign(auth.NewHmacKey("hmac-2", []byte("forged")), claims(nil)), "", 401},
+		{"expired", "Bearer " + sign(hmacKey, claims(func(c *predefine.LoginClaims) { c.ExpiredAt = gut.Ptr(now.Add(-time.Minute)) })), "", 401},
+		{"not yet valid", "Bearer " + sign(hmacKey, claims(func(c *predefine.LoginClaims) { c.NotBefore = gut.Ptr(now.Add(time.Hour)) })), "", 401},
+		{"wrong issuer", "Bearer " + sign(hmacKey, claims(func(c *predefine.LoginClaims) { c.Issuer = gut.Ptr("other") })), "", 401},
+		{"wrong audience", "Bearer " + sign(hmacKey, claims(func(c *predefine.LoginClaims) { c.Audience = []string{"other"} })), "", 401},
+		{"missing expiry", "Bearer " + sign(hmacKey, claims(func(c *predefine.LoginClaims) { c.ExpiredAt = nil })), "", 401},
+	}
+	for _, c := range cases {
+		if status := request(c.header, c.cookie); status != c.expected {
+			t.Errorf("%s: expected status %d, got %d", c.name, c.expected, status)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package authtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Provider struct {
+	Server   *httptest.Server
+	Key      *rsa.PrivateKey
+	KeyId    string
+	ClientId string
+	Claims   map[string]any
+	Mutex    sync.Mutex
+	Grants   map[string]*Grant
+}
+
+type Grant struct {
+	Challenge string
+	Nonce     string
+}
+
+func NewProvider(clientId string, claims map[string]any) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &Provider{
+		Key:      key,
+		KeyId:    "test",
+		ClientId: clientId,
+		Claims:   claims,
+		Grants:   make(map[string]*Grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", provider.HandleDiscovery)
+	mux.HandleFunc("GET /jwks", provider.HandleJwks)
+	mux.HandleFunc("POST /token", provider.HandleToken)
+	provider.Server = httptest.NewServer(mux)
+
+	return provider, nil
+}
+
+func (r *Provider) Issuer() string {
+	return r.Server.URL
+}
+
+func (r *Provider) Close() {
+	r.Server.Close()
+}
+
+func (r *Provider) Authorize(authUrl string) (code string, state string, err error) {
+	parsed, err := url.Parse(authUrl)
+	if err != nil {
+		return "", "", err
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != r.ClientId {
+		return "", "", errors.New("unknown client id")
+	}
+	if query.Get("code_challenge_method") != "S256" {
+		return "", "", errors.New("missing pkce challenge")
+	}
+
+	// * register grant as if the user consented
+	code = rand.Text()
+	r.Mutex.Lock()
+	r.Grants[code] = &Grant{
+		Challenge: query.Get("code_challenge"),
+		Nonce:     query.Get("nonce"),
+	}
+	r.Mutex.Unlock()
+
+	return code, query.Get("state"), nil
+}
+
+func (r *Provider) HandleDiscovery(w http.ResponseWriter, req *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"issuer":                                r.Issuer(),
+		"authorization_endpoint":                r.Issuer() + "/authorize",
+		"token_endpoint":                        r.Issuer() + "/token",
+		"jwks_uri":                              r.Issuer() + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (r *Provider) HandleJwks(w http.ResponseWriter, req *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]any{
+			{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": r.KeyId,
+				"n":   base64.RawURLEncoding.EncodeToString(r.Key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(r.Key.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func (r *Provider) HandleToken(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		writeJson(w, http.StatusBadRequest, map[string]any{"error": "invalid_request"})
+		return
+	}
+
+	// * consume grant
+	r.Mutex.Lock()
+	grant, ok := r.Grants[req.PostForm.Get("code")]
+	delete(r.Grants, req.PostForm.Get("code"))
+	r.Mutex.Unlock()
+	if !ok {
+		writeJson(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+
+	// * verify pkce
+	digest := sha256.Sum256([]byte(req.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(digest[:]) != grant.Challenge {
+		writeJson(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+
+	// * sign id token
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   r.Issuer(),
+		"aud":   r.ClientId,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": grant.Nonce,
+	}
+	for key, value := range r.Claims {
+		claims[key] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = r.KeyId
+	idToken, err := token.SignedString(r.Key)
+	if err != nil {
+		writeJson(w, http.StatusInternalServerError, map[string]any{"error": "server_error"})
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -2,6 +2,7 @@ package predefine
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/bsthun/gut"
@@ -19,55 +20,104 @@ type OidcClaims struct {
 
 type LoginClaims struct {
 	UserId    *uint64    `json:"userId"`
+	Issuer    *string    `json:"iss"`
+	Audience  []string   `json:"aud"`
+	IssuedAt  *time.Time `json:"iat"`
+	NotBefore *time.Time `json:"nbf"`
 	ExpiredAt *time.Time `json:"exp"`
 }
 
 func (r *LoginClaims) GetExpirationTime() (*jwt.NumericDate, error) {
-	if r.ExpiredAt == nil {
-		return nil, nil
-	}
-	return &jwt.NumericDate{
-		Time: *r.ExpiredAt,
-	}, nil
+	return numericDate(r.ExpiredAt), nil
 }
 
 func (r *LoginClaims) GetIssuedAt() (*jwt.NumericDate, error) {
-	return nil, nil
+	return numericDate(r.IssuedAt), nil
 }
 
 func (r *LoginClaims) GetNotBefore() (*jwt.NumericDate, error) {
-	return nil, nil
+	return numericDate(r.NotBefore), nil
 }
 
 func (r *LoginClaims) GetIssuer() (string, error) {
-	return "", nil
+	return gut.Val(r.Issuer), nil
 }
 
 func (r *LoginClaims) GetSubject() (string, error) {
+	if r.UserId == nil {
+		return "", nil
+	}
 	return gut.IdEncode(*r.UserId), nil
 }
 
 func (r *LoginClaims) GetAudience() (jwt.ClaimStrings, error) {
-	return nil, nil
+	return r.Audience, nil
 }
 
 func (r *LoginClaims) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]any{
-		"userId": gut.IdEncode(*r.UserId),
-	})
+	raw := make(map[string]any)
+	if r.UserId != nil {
+		raw["userId"] = gut.IdEncode(*r.UserId)
+	}
+	if r.Issuer != nil {
+		raw["iss"] = *r.Issuer
+	}
+	if len(r.Audience) > 0 {
+		raw["aud"] = r.Audience
+	}
+	if r.IssuedAt != nil {
+		raw["iat"] = r.IssuedAt.Unix()
+	}
+	if r.NotBefore != nil {
+		raw["nbf"] = r.NotBefore.Unix()
+	}
+	if r.ExpiredAt != nil {
+		raw["exp"] = r.ExpiredAt.Unix()
+	}
+	return json.Marshal(raw)
 }
 
 func (r *LoginClaims) UnmarshalJSON(data []byte) error {
-	var raw map[string]any
+	var raw struct {
+		UserId    *string          `json:"userId"`
+		Issuer    *string          `json:"iss"`
+		Audience  jwt.ClaimStrings `json:"aud"`
+		IssuedAt  *jwt.NumericDate `json:"iat"`
+		NotBefore *jwt.NumericDate `json:"nbf"`
+		ExpiredAt *jwt.NumericDate `json:"exp"`
+	}
 	err := json.Unmarshal(data, &raw)
 	if err != nil {
 		return err
 	}
 
-	userId, err := gut.IdDecode(raw["userId"].(string))
+	if raw.UserId == nil {
+		return errors.New("missing userId claim")
+	}
+	userId, err := gut.IdDecode(*raw.UserId)
 	if err != nil {
 		return err
 	}
+
 	r.UserId = &userId
+	r.Issuer = raw.Issuer
+	r.Audience = raw.Audience
+	r.IssuedAt = timeOf(raw.IssuedAt)
+	r.NotBefore = timeOf(raw.NotBefore)
+	r.ExpiredAt = timeOf(raw.ExpiredAt)
 	return nil
 }
+
+func numericDate(t *time.Time) *jwt.NumericDate {
+	if t == nil {
+		return nil
+	}
+	return jwt.NewNumericDate(*t)
+}
+
+func timeOf(date *jwt.NumericDate) *time.Time {
+	if date == nil {
+		return nil
+	}
+	return &date.Time
+}
@@ -1,8 +1,12 @@
 package predefine
 
-type Environment uint8
+import (
+	"go.scnd.dev/open/polygon"
+)
+
+type Environment = polygon.Environment
 
 var (
-	EnvironmentDevelopment Environment = 1
-	EnvironmentProduction  Environment = 2
+	EnvironmentDevelopment = polygon.EnvironmentDevelopment
+	EnvironmentProduction  = polygon.EnvironmentProduction
 )
@@ -1,8 +1,17 @@
 package predefine
 
 import (
+	"fmt"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
 )
 
 func NewHttpClient() *http.Client {
@@ -10,3 +19,62 @@ func NewHttpClient() *http.Client {
 		Timeout: 10 * time.Second,
 	}
 }
+
+func NewTracedHttpClient(polygon polygon.Polygon) *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &TracedTransport{
+			Polygon: polygon,
+			Base:    http.DefaultTransport,
+		},
+	}
+}
+
+type TracedTransport struct {
+	Polygon polygon.Polygon
+	Base    http.RoundTripper
+}
+
+func (r *TracedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	started := time.Now()
+	method := request.Method
+	host := request.URL.Host
+
+	// * start client span
+	name := fmt.Sprintf("HTTP %s %s", method, host)
+	ctx, tracingSpan := r.Polygon.Tracer().Start(
+		request.Context(),
+		name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.ServerAddress(host),
+			semconv.URLPath(request.URL.Path),
+		),
+	)
+	defer tracingSpan.End()
+
+	// * inject propagation headers
+	request = request.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	// * proceed to base transport
+	response, err := r.Base.RoundTrip(request)
+	duration := time.Since(started).Milliseconds()
+	tracingSpan.SetAttributes(attribute.Int64("http.client.duration", duration))
+	if err != nil {
+		tracingSpan.RecordError(err)
+		tracingSpan.SetStatus(codes.Error, err.Error())
+		r.Polygon.Instrument().HttpClientDurationRecord(ctx, duration, method, host, 0)
+		return nil, err
+	}
+
+	// * record response
+	tracingSpan.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		tracingSpan.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+	}
+	r.Polygon.Instrument().HttpClientDurationRecord(ctx, duration, method, host, response.StatusCode)
+
+	return response, nil
+}
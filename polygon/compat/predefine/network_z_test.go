@@ -0,0 +1,92 @@
+package predefine_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/compat/predefine"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestTracedHttpClient(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	traceparent := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	caller := recorder.Span(context.Background(), "caller", "test", nil)
+	client := predefine.NewTracedHttpClient(recorder)
+	request := func(target string) (*http.Response, error) {
+		req, _ := http.NewRequestWithContext(caller.Context(), http.MethodGet, target+"/users", nil)
+		return client.Do(req)
+	}
+
+	// Successful call propagates the caller trace through a client span
+	res, err := request(server.URL)
+	if err != nil {
+		t.Fatalf("Failed request: %v", err)
+	}
+	_ = res.Body.Close()
+
+	host, _ := url.Parse(server.URL)
+	ended := recorder.Ended("HTTP GET " + host.Host)
+	if ended == nil {
+		t.Fatal("Expected ended client span")
+	}
+	callerContext := trace.SpanContextFromContext(caller.Context())
+	if ended.SpanKind() != trace.SpanKindClient || ended.Parent().SpanID() != callerContext.SpanID() {
+		t.Errorf("Expected client span under caller, got kind %v parent %s", ended.SpanKind(), ended.Parent().SpanID())
+	}
+	expected := "00-" + callerContext.TraceID().String() + "-" + ended.SpanContext().SpanID().String() + "-01"
+	if traceparent != expected {
+		t.Errorf("Expected traceparent %s, got %q", expected, traceparent)
+	}
+	attributes := attribute.NewSet(ended.Attributes()...)
+	if status, _ := attributes.Value(semconv.HTTPResponseStatusCodeKey); status.AsInt64() != http.StatusCreated {
+		t.Errorf("Expected status code attribute, got %v", status.AsInt64())
+	}
+	if ended.Status().Code == codes.Error {
+		t.Errorf("Expected successful span, got %v", ended.Status())
+	}
+
+	// Transport failure marks the client span as failed
+	if _, err := request(closed.URL); err == nil {
+		t.Fatal("Expected request to closed server to fail")
+	}
+	host, _ = url.Parse(closed.URL)
+	failed := recorder.Ended("HTTP GET " + host.Host)
+	if failed == nil || failed.Status().Code != codes.Error {
+		t.Fatalf("Expected failed client span, got %v", failed)
+	}
+
+	// One duration sample per call
+	points := recorder.Histogram("app.http.client.duration")
+	count := make(map[int64]uint64)
+	for _, point := range points {
+		status, _ := point.Attributes.Value(semconv.HTTPResponseStatusCodeKey)
+		count[status.AsInt64()] += point.Count
+	}
+	if len(count) != 2 || count[http.StatusCreated] != 1 || count[0] != 1 {
+		t.Errorf("Expected one sample for each call, got %v", count)
+	}
+}
@@ -2,43 +2,111 @@ package response
 
 import (
 	"errors"
+	"net/http"
 	"strings"
 
 	"github.com/bsthun/gut"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
 	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry"
 )
 
+type ErrorFormat string
+
+const (
+	ErrorFormatEnvelope ErrorFormat = "envelope"
+	ErrorFormatProblem  ErrorFormat = "problem"
+)
+
+type ErrorDetail struct {
+	Status  int
+	Message *string
+	Error   *string
+	Items   []*ErrorResponseItem
+	Fields  []*ProblemField
+}
+
+func ErrorHandler(format ErrorFormat) fiber.ErrorHandler {
+	switch format {
+	case ErrorFormatProblem:
+		return HandleProblem
+	default:
+		return HandleError
+	}
+}
+
 func HandleError(c fiber.Ctx, err error) error {
-	// * construct success
-	success := false
+	detail := ResolveError(c, err)
+	return c.Status(detail.Status).JSON(&ErrorResponse{
+		Success: gut.Ptr(false),
+		Message: detail.Message,
+		Error:   detail.Error,
+		TraceId: TraceId(c),
+		Items:   detail.Items,
+	})
+}
+
+func HandleProblem(c fiber.Ctx, err error) error {
+	detail := ResolveError(c, err)
+	return c.Status(detail.Status).JSON(&ProblemResponse{
+		Type:     gut.Ptr(ProblemTypeBlank),
+		Title:    gut.Ptr(http.StatusText(detail.Status)),
+		Status:   &detail.Status,
+		Detail:   detail.Message,
+		Instance: gut.Ptr(strings.Clone(c.Path())),
+		Errors:   detail.Fields,
+		TraceId:  TraceId(c),
+		Items:    detail.Items,
+	}, ProblemContentType)
+}
+
+func ResolveError(c fiber.Ctx, err error) *ErrorDetail {
+	development := Environment(c) == polygon.EnvironmentDevelopment
+
+	// * case of `*PanicError`
+	var panicError *PanicError
+	if errors.As(err, &panicError) {
+		var estr *string
+		if development {
+			estr = gut.Ptr(panicError.Error() + "\n" + panicError.Stack)
+		}
+		return &ErrorDetail{
+			Status:  fiber.StatusInternalServerError,
+			Message: gut.Ptr("internal server error"),
+			Error:   estr,
+		}
+	}
 
 	// * case of `*fiber.Error`
 	var fiberError *fiber.Error
 	if errors.As(err, &fiberError) {
-		return c.Status(fiberError.Code).JSON(&ErrorResponse{
-			Success: &success,
+		return &ErrorDetail{
+			Status:  fiberError.Code,
 			Message: &fiberError.Message,
-		})
+		}
 	}
 
 	// * case of `*span.Error`
 	var spanError *span.Error
 	if errors.As(err, &spanError) {
-		if spanError.Items[0].Error != nil {
-			estr := spanError.Items[0].Error.Error()
-			return c.Status(fiber.StatusBadRequest).JSON(&ErrorResponse{
-				Success: &success,
-				Message: spanError.Items[0].Message,
-				Error:   &estr,
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(&ErrorResponse{
-			Success: gut.Ptr(false),
+		detail := &ErrorDetail{
+			Status:  spanError.Status(),
 			Message: spanError.Items[0].Message,
-			Error:   nil,
-		})
+		}
+		var validatorErr validator.ValidationErrors
+		if errors.As(err, &validatorErr) {
+			detail.Fields = ValidationFields(validatorErr)
+		}
+		if development {
+			if spanError.Items[0].Error != nil {
+				detail.Error = gut.Ptr(spanError.Items[0].Error.Error())
+			}
+			detail.Items = ErrorItems(spanError)
+		}
+		return detail
 	}
 
 	// * case of `validator.ValidationErrors`
@@ -51,16 +119,86 @@ func HandleError(c fiber.Ctx, err error) error {
 
 		message := strings.Join(lists[:], ", ")
 
-		return c.Status(fiber.StatusBadRequest).JSON(&ErrorResponse{
-			Success: gut.Ptr(false),
+		return &ErrorDetail{
+			Status:  fiber.StatusBadRequest,
 			Message: gut.Ptr("validation failed on " + message),
 			Error:   gut.Ptr(validatorErr.Error()),
-		})
+			Fields:  ValidationFields(validatorErr),
+		}
 	}
 
-	return c.Status(fiber.StatusInternalServerError).JSON(&ErrorResponse{
-		Success: gut.Ptr(false),
+	// * case of sentinel error
+	if status, ok := span.ErrorSentinelStatus(err); ok {
+		detail := &ErrorDetail{
+			Status:  status,
+			Message: gut.Ptr(http.StatusText(status)),
+		}
+		if development {
+			detail.Error = gut.Ptr(err.Error())
+		}
+		return detail
+	}
+
+	// * hide unknown error outside development
+	var estr *string
+	if development {
+		estr = gut.Ptr(err.Error())
+	}
+	return &ErrorDetail{
+		Status:  fiber.StatusInternalServerError,
 		Message: gut.Ptr("unknown server error"),
-		Error:   gut.Ptr(err.Error()),
-	})
+		Error:   estr,
+	}
+}
+
+func ValidationFields(validatorErr validator.ValidationErrors) []*ProblemField {
+	fields := make([]*ProblemField, 0, len(validatorErr))
+	for _, err := range validatorErr {
+		field := &ProblemField{
+			Field: gut.Ptr(err.Field()),
+			Tag:   gut.Ptr(err.Tag()),
+		}
+		if err.Param() != "" {
+			field.Param = gut.Ptr(err.Param())
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func ErrorItems(spanError *span.Error) []*ErrorResponseItem {
+	items := make([]*ErrorResponseItem, 0, len(spanError.Items))
+	for _, item := range spanError.Items {
+		responseItem := &ErrorResponseItem{
+			Message: item.Message,
+		}
+		if item.Trace != nil {
+			responseItem.Caller = gut.Ptr(item.Trace.String())
+		}
+		if item.Error != nil {
+			responseItem.Error = gut.Ptr(item.Error.Error())
+		}
+		if item.Dimension != nil {
+			responseItem.Dimension = gut.Ptr(string(*item.Dimension))
+			responseItem.Scope = gut.Ptr(string(*item.Scope))
+		}
+		items = append(items, responseItem)
+	}
+	return items
+}
+
+func TraceId(c fiber.Ctx) *string {
+	spanContext := trace.SpanContextFromContext(c.Context())
+	if !spanContext.HasTraceID() {
+		return nil
+	}
+	return gut.Ptr(spanContext.TraceID().String())
+}
+
+func Environment(c fiber.Ctx) polygon.Environment {
+	// * resolve environment from request span
+	if s, ok := telemetry.SpanFromCtx(c).(*span.Wrapper); ok && s.Span.Context.Polygon.Config().AppEnvironment != nil {
+		return *s.Span.Context.Polygon.Config().AppEnvironment
+	}
+	return polygon.EnvironmentProduction
 }
@@ -0,0 +1,188 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/codes"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/compat/response"
+	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+type body struct {
+	Name *string `json:"name" validate:"required"`
+}
+
+func newApp(t *testing.T, environment polygon.Environment, format response.ErrorFormat, recoverFirst bool) (*fiber.App, *telemetrytest.Recorder) {
+	recorder, err := telemetrytest.New(&polygon.Config{
+		AppEnvironment: &environment,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: response.ErrorHandler(format)})
+	if recoverFirst {
+		app.Use(response.Recover(format), recorder.TracerMiddleware())
+	} else {
+		app.Use(recorder.TracerMiddleware(), response.Recover(format))
+	}
+
+	app.Get("/panic", func(c fiber.Ctx) error {
+		panic("boom")
+	})
+	app.Get("/span", func(c fiber.Ctx) error {
+		s := telemetry.SpanFromCtx(c)
+		return s.Error("user not found", s.Fork("database").Error("query failed", errors.New("pq: relation users")))
+	})
+	app.Get("/sentinel", func(c fiber.Ctx) error {
+		return fmt.Errorf("select * from users where id=42: %w", span.ErrNotFound)
+	})
+	app.Get("/timeout", func(c fiber.Ctx) error {
+		return telemetry.SpanFromCtx(c).ErrorDimension(polygon.DimensionTypeTimeout, polygon.DimensionScopeDatabase, "query timed out", nil)
+	})
+	app.Get("/unknown", func(c fiber.Ctx) error {
+		return errors.New("pq: password authentication failed")
+	})
+	app.Get("/validation", func(c fiber.Ctx) error {
+		return telemetry.SpanFromCtx(c).Error("invalid body", validator.New().Struct(&body{}))
+	})
+
+	return app, recorder
+}
+
+func request(t *testing.T, app *fiber.App, path string) (*http.Response, map[string]any) {
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("Failed request %s: %v", path, err)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	result := make(map[string]any)
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("Failed to decode %s response %q: %v", path, raw, err)
+	}
+	return res, result
+}
+
+func TestRecover(t *testing.T) {
+	for _, recoverFirst := range []bool{false, true} {
+		for _, environment := range []polygon.Environment{polygon.EnvironmentDevelopment, polygon.EnvironmentProduction} {
+			name := fmt.Sprintf("recoverFirst=%v/environment=%d", recoverFirst, environment)
+			app, recorder := newApp(t, environment, response.ErrorFormatEnvelope, recoverFirst)
+
+			res, result := request(t, app, "/panic")
+			if res.StatusCode != http.StatusInternalServerError || result["message"] != "internal server error" {
+				t.Errorf("%s: unexpected response %d %v", name, res.StatusCode, result)
+			}
+			stack, _ := result["error"].(string)
+			if environment == polygon.EnvironmentDevelopment && !strings.Contains(stack, "panic: boom") {
+				t.Errorf("%s: expected stack in development, got %q", name, stack)
+			}
+			if environment == polygon.EnvironmentProduction && stack != "" {
+				t.Errorf("%s: expected no stack in production, got %q", name, stack)
+			}
+			if result["traceId"] == nil {
+				t.Errorf("%s: expected trace id", name)
+			}
+
+			// Panic lands on the live request span regardless of order
+			ended := recorder.Ended("HTTP GET /panic")
+			if ended == nil {
+				t.Fatalf("%s: expected ended request span", name)
+			}
+			if ended.Status().Code != codes.Error {
+				t.Errorf("%s: expected error status, got %v", name, ended.Status())
+			}
+			events := 0
+			for _, event := range ended.Events() {
+				if event.Name == "panic" {
+					events++
+				}
+			}
+			if events != 1 {
+				t.Errorf("%s: expected exactly one panic event, got %d", name, events)
+			}
+			points := recorder.Sum("app.error.count")
+			if len(points) != 1 || points[0].Value != 1 {
+				t.Errorf("%s: expected one fatal error count, got %v", name, points)
+			}
+		}
+	}
+}
+
+func TestHandleErrorEnvironment(t *testing.T) {
+	app, _ := newApp(t, polygon.EnvironmentDevelopment, response.ErrorFormatEnvelope, false)
+	_, result := request(t, app, "/span")
+	if result["error"] != "pq: relation users" || len(result["items"].([]any)) != 2 {
+		t.Errorf("Expected error chain in development, got %v", result)
+	}
+	_, result = request(t, app, "/unknown")
+	if result["error"] == nil {
+		t.Errorf("Expected raw unknown error in development, got %v", result)
+	}
+
+	app, _ = newApp(t, polygon.EnvironmentProduction, response.ErrorFormatEnvelope, false)
+	_, result = request(t, app, "/span")
+	if result["error"] != nil || result["items"] != nil || result["traceId"] == nil {
+		t.Errorf("Expected sanitized span error in production, got %v", result)
+	}
+	_, result = request(t, app, "/unknown")
+	if result["error"] != nil || result["message"] != "unknown server error" {
+		t.Errorf("Expected hidden unknown error in production, got %v", result)
+	}
+}
+
+func TestHandleErrorStatus(t *testing.T) {
+	app, _ := newApp(t, polygon.EnvironmentProduction, response.ErrorFormatEnvelope, false)
+
+	res, result := request(t, app, "/sentinel")
+	if res.StatusCode != http.StatusNotFound || result["message"] != "Not Found" || result["error"] != nil {
+		t.Errorf("Expected sanitized not found, got %d %v", res.StatusCode, result)
+	}
+	res, _ = request(t, app, "/timeout")
+	if res.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("Expected gateway timeout, got %d", res.StatusCode)
+	}
+	res, _ = request(t, app, "/span")
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected bad request, got %d", res.StatusCode)
+	}
+
+	app, _ = newApp(t, polygon.EnvironmentDevelopment, response.ErrorFormatEnvelope, false)
+	_, result = request(t, app, "/sentinel")
+	if !strings.Contains(fmt.Sprint(result["error"]), "select * from users") {
+		t.Errorf("Expected raw sentinel error in development, got %v", result)
+	}
+}
+
+func TestHandleProblem(t *testing.T) {
+	app, _ := newApp(t, polygon.EnvironmentProduction, response.ErrorFormatProblem, false)
+
+	res, result := request(t, app, "/validation")
+	if res.Header.Get("Content-Type") != response.ProblemContentType {
+		t.Errorf("Expected problem content type, got %s", res.Header.Get("Content-Type"))
+	}
+	if result["status"] != float64(http.StatusBadRequest) || result["title"] != "Bad Request" || result["instance"] != "/validation" {
+		t.Errorf("Unexpected problem %v", result)
+	}
+	fields, _ := result["errors"].([]any)
+	if len(fields) != 1 || fields[0].(map[string]any)["field"] != "Name" {
+		t.Errorf("Expected field errors for wrapped validation, got %v", result["errors"])
+	}
+
+	res, result = request(t, app, "/panic")
+	if res.StatusCode != http.StatusInternalServerError || result["type"] != response.ProblemTypeBlank {
+		t.Errorf("Expected problem for recovered panic, got %d %v", res.StatusCode, result)
+	}
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"go.scnd.dev/open/polygon/package/telemetry"
+)
+
+type PanicError = telemetry.PanicError
+
+func Recover(format ...ErrorFormat) fiber.Handler {
+	handler := HandleError
+	if len(format) > 0 {
+		handler = ErrorHandler(format[0])
+	}
+
+	return func(c fiber.Ctx) (err error) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+
+			// * reuse panic already recorded by telemetry middleware
+			if recorded, ok := value.(*PanicError); ok {
+				err = handler(c, recorded)
+				return
+			}
+
+			// * record panic on request span
+			var panicErr error = telemetry.NewPanicError(value)
+			if s := telemetry.SpanFromCtx(c); s != nil {
+				panicErr = telemetry.RecordPanic(s, panicErr.(*PanicError))
+			}
+
+			err = handler(c, panicErr)
+		}()
+
+		return c.Next()
+	}
+}
@@ -1,6 +1,31 @@
 package response
 
+import "github.com/bsthun/gut"
+
+const PageLimit int32 = 20
+
 type Paginate struct {
 	Limit  *int32 `json:"limit" validate:"required,gte=1,lte=100"`
 	Offset *int32 `json:"offset" validate:"required,gte=0"`
 }
+
+type CursorPaginate struct {
+	Limit  *int32  `json:"limit" validate:"required,gte=1,lte=100"`
+	Cursor *string `json:"cursor" validate:"omitempty,base64rawurl"`
+}
+
+func (r *Paginate) Values() (int32, int32) {
+	limit := gut.Val(r.Limit)
+	if limit <= 0 {
+		limit = PageLimit
+	}
+	return limit, max(gut.Val(r.Offset), 0)
+}
+
+func (r *CursorPaginate) Size() int32 {
+	limit := gut.Val(r.Limit)
+	if limit <= 0 {
+		limit = PageLimit
+	}
+	return limit
+}
@@ -1,7 +1,17 @@
 package response
 
 type ErrorResponse struct {
-	Success *bool   `json:"success"`
-	Message *string `json:"message,omitempty"`
-	Error   *string `json:"error,omitempty"`
+	Success *bool                `json:"success"`
+	Message *string              `json:"message,omitempty"`
+	Error   *string              `json:"error,omitempty"`
+	TraceId *string              `json:"traceId,omitempty"`
+	Items   []*ErrorResponseItem `json:"items,omitempty"`
+}
+
+type ErrorResponseItem struct {
+	Message   *string `json:"message,omitempty"`
+	Caller    *string `json:"caller,omitempty"`
+	Error     *string `json:"error,omitempty"`
+	Dimension *string `json:"dimension,omitempty"`
+	Scope     *string `json:"scope,omitempty"`
 }
@@ -0,0 +1,164 @@
+package response
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+type Page[T any] struct {
+	Items  []T     `json:"items"`
+	Total  *int64  `json:"total,omitempty"`
+	Limit  *int32  `json:"limit"`
+	Offset *int32  `json:"offset,omitempty"`
+	Next   *string `json:"next,omitempty"`
+	Prev   *string `json:"prev,omitempty"`
+}
+
+type Cursor struct {
+	Key      json.RawMessage `json:"k"`
+	Backward bool            `json:"b,omitempty"`
+}
+
+// PageList matches sqlc list queries of the form XxxList(ctx, XxxListParams).
+type PageList[P any, R any] func(ctx context.Context, params P) ([]R, error)
+
+// PageParams builds the list query params from the resolved limit and offset.
+type PageParams[P any] func(limit int32, offset int32) P
+
+type PageCount func(ctx context.Context) (int64, error)
+
+func NewPage[T any](paginate *Paginate, items []T, total int64) *Page[T] {
+	if items == nil {
+		items = make([]T, 0)
+	}
+	limit, offset := paginate.Values()
+	return &Page[T]{
+		Items:  items,
+		Total:  &total,
+		Limit:  &limit,
+		Offset: &offset,
+	}
+}
+
+func ListPage[P any, R any, T any](s polygon.Span, paginate *Paginate, list PageList[P, R], params PageParams[P], count PageCount, mapper func(row R) T) (*Page[T], error) {
+	// * query rows
+	rows, err := list(s.Context(), params(paginate.Values()))
+	if err != nil {
+		return nil, s.Error("unable to list items", err)
+	}
+
+	// * query total
+	total, err := count(s.Context())
+	if err != nil {
+		return nil, s.Error("unable to count items", err)
+	}
+
+	// * map rows
+	items := make([]T, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, mapper(row))
+	}
+
+	return NewPage(paginate, items, total), nil
+}
+
+// NewCursorPage expects up to limit+1 rows fetched after the cursor key in
+// ascending order, or before it in descending order when the cursor is backward.
+func NewCursorPage[T any, K any](paginate *CursorPaginate, items []T, key func(item T) K) (*Page[T], error) {
+	cursor, err := DecodeCursor(paginate.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	backward := cursor != nil && cursor.Backward
+
+	// * trim the extra fetched item
+	limit := paginate.Size()
+	more := len(items) > int(limit)
+	if more {
+		items = items[:int(limit)]
+	}
+
+	// * restore ascending order for backward page
+	page := &Page[T]{
+		Items: make([]T, len(items)),
+		Limit: &limit,
+	}
+	copy(page.Items, items)
+	if backward {
+		slices.Reverse(page.Items)
+	}
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	// * construct next cursor when rows follow this page
+	if backward || more {
+		next, err := EncodeCursor(key(page.Items[len(page.Items)-1]), false)
+		if err != nil {
+			return nil, err
+		}
+		page.Next = &next
+	}
+
+	// * construct previous cursor when rows precede this page
+	if (backward && more) || (!backward && cursor != nil) {
+		prev, err := EncodeCursor(key(page.Items[0]), true)
+		if err != nil {
+			return nil, err
+		}
+		page.Prev = &prev
+	}
+
+	return page, nil
+}
+
+func (r *CursorPaginate) Backward() (bool, error) {
+	cursor, err := DecodeCursor(r.Cursor)
+	if err != nil {
+		return false, err
+	}
+	return cursor != nil && cursor.Backward, nil
+}
+
+func EncodeCursor(key any, backward bool) (string, error) {
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return "", span.NewError(nil, "unable to encode cursor key", err)
+	}
+	cursor, err := json.Marshal(&Cursor{
+		Key:      encoded,
+		Backward: backward,
+	})
+	if err != nil {
+		return "", span.NewError(nil, "unable to encode cursor", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+func DecodeCursor(cursor *string) (*Cursor, error) {
+	if cursor == nil || *cursor == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil {
+		return nil, span.NewError(nil, "invalid cursor encoding", err)
+	}
+	result := new(Cursor)
+	if err := json.Unmarshal(decoded, result); err != nil {
+		return nil, span.NewError(nil, "invalid cursor payload", err)
+	}
+	return result, nil
+}
+
+func (r *Cursor) Scan(target any) error {
+	return json.Unmarshal(r.Key, target)
+}
+
+func (r *CursorPaginate) FetchLimit() int64 {
+	return int64(r.Size()) + 1
+}
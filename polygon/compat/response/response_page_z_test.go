@@ -0,0 +1,161 @@
+package response_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/bsthun/gut"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/compat/response"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+type item struct {
+	Id int64
+}
+
+func fetch(t *testing.T, rows []item, paginate *response.CursorPaginate) *response.Page[item] {
+	cursor, err := response.DecodeCursor(paginate.Cursor)
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+
+	// Emulate a keyset querier fetching limit+1 rows around the cursor key
+	result := make([]item, 0)
+	switch {
+	case cursor == nil:
+		result = append(result, rows...)
+	case cursor.Backward:
+		var key int64
+		_ = cursor.Scan(&key)
+		for _, row := range slices.Backward(rows) {
+			if row.Id < key {
+				result = append(result, row)
+			}
+		}
+	default:
+		var key int64
+		_ = cursor.Scan(&key)
+		for _, row := range rows {
+			if row.Id > key {
+				result = append(result, row)
+			}
+		}
+	}
+	result = result[:min(len(result), int(paginate.FetchLimit()))]
+
+	page, err := response.NewCursorPage(paginate, result, func(row item) int64 { return row.Id })
+	if err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+	return page
+}
+
+func ids(page *response.Page[item]) []int64 {
+	result := make([]int64, 0, len(page.Items))
+	for _, row := range page.Items {
+		result = append(result, row.Id)
+	}
+	return result
+}
+
+func TestCursorPaging(t *testing.T) {
+	rows := []item{{1}, {2}, {3}, {4}, {5}}
+	limit := gut.Ptr[int32](2)
+
+	expect := func(name string, page *response.Page[item], items []int64, next bool, prev bool) {
+		if !slices.Equal(ids(page), items) {
+			t.Errorf("%s: expected items %v, got %v", name, items, ids(page))
+		}
+		if (page.Next != nil) != next {
+			t.Errorf("%s: expected next %v, got %v", name, next, page.Next != nil)
+		}
+		if (page.Prev != nil) != prev {
+			t.Errorf("%s: expected prev %v, got %v", name, prev, page.Prev != nil)
+		}
+	}
+
+	// Forward from the first page to the end
+	p1 := fetch(t, rows, &response.CursorPaginate{Limit: limit})
+	expect("first", p1, []int64{1, 2}, true, false)
+	p2 := fetch(t, rows, &response.CursorPaginate{Limit: limit, Cursor: p1.Next})
+	expect("second", p2, []int64{3, 4}, true, true)
+	p3 := fetch(t, rows, &response.CursorPaginate{Limit: limit, Cursor: p2.Next})
+	expect("last", p3, []int64{5}, false, true)
+
+	// Backward to the first page again
+	b2 := fetch(t, rows, &response.CursorPaginate{Limit: limit, Cursor: p3.Prev})
+	expect("back second", b2, []int64{3, 4}, true, true)
+	b1 := fetch(t, rows, &response.CursorPaginate{Limit: limit, Cursor: b2.Prev})
+	expect("back first", b1, []int64{1, 2}, true, false)
+
+	// Forward again from a page reached backward
+	f2 := fetch(t, rows, &response.CursorPaginate{Limit: limit, Cursor: b1.Next})
+	expect("forward again", f2, []int64{3, 4}, true, true)
+}
+
+// itemListParams mirrors the params struct sqlc generates for a list query
+type itemListParams struct {
+	Limit  int32
+	Offset int32
+}
+
+type querier struct {
+	rows []item
+}
+
+func (r *querier) ItemList(ctx context.Context, arg itemListParams) ([]item, error) {
+	start := min(int(arg.Offset), len(r.rows))
+	return r.rows[start:min(start+int(arg.Limit), len(r.rows))], nil
+}
+
+func (r *querier) ItemCount(ctx context.Context) (int64, error) {
+	return int64(len(r.rows)), nil
+}
+
+func TestListPage(t *testing.T) {
+	recorder, err := telemetrytest.New(new(polygon.Config))
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	s := recorder.Span(context.Background(), "list", "test", nil)
+	defer s.End()
+
+	rows := make([]item, 0, 30)
+	for i := range 30 {
+		rows = append(rows, item{int64(i + 1)})
+	}
+	q := &querier{rows: rows}
+	params := func(limit int32, offset int32) itemListParams {
+		return itemListParams{Limit: limit, Offset: offset}
+	}
+	mapper := func(row item) int64 { return row.Id }
+
+	// Explicit limit and offset are passed through the params builder
+	page, err := response.ListPage(s, &response.Paginate{Limit: gut.Ptr[int32](5), Offset: gut.Ptr[int32](10)}, q.ItemList, params, q.ItemCount, mapper)
+	if err != nil {
+		t.Fatalf("Failed to list page: %v", err)
+	}
+	if !slices.Equal(page.Items, []int64{11, 12, 13, 14, 15}) || *page.Total != 30 || *page.Limit != 5 || *page.Offset != 10 {
+		t.Errorf("Unexpected page %v total %d limit %d offset %d", page.Items, *page.Total, *page.Limit, *page.Offset)
+	}
+
+	// Missing limit and offset fall back to defaults
+	page, err = response.ListPage(s, new(response.Paginate), q.ItemList, params, q.ItemCount, mapper)
+	if err != nil {
+		t.Fatalf("Failed to list page: %v", err)
+	}
+	if len(page.Items) != int(response.PageLimit) || page.Items[0] != 1 || *page.Limit != response.PageLimit || *page.Offset != 0 {
+		t.Errorf("Expected default page, got %d items limit %d offset %d", len(page.Items), *page.Limit, *page.Offset)
+	}
+
+	// Cursor pages default the limit as well
+	cursor, err := response.NewCursorPage(new(response.CursorPaginate), rows, func(row item) int64 { return row.Id })
+	if err != nil {
+		t.Fatalf("Failed to create cursor page: %v", err)
+	}
+	if len(cursor.Items) != int(response.PageLimit) || cursor.Next == nil {
+		t.Errorf("Expected default cursor page, got %d items", len(cursor.Items))
+	}
+}
@@ -0,0 +1,23 @@
+package response
+
+const (
+	ProblemContentType = "application/problem+json"
+	ProblemTypeBlank   = "about:blank"
+)
+
+type ProblemResponse struct {
+	Type     *string              `json:"type"`
+	Title    *string              `json:"title"`
+	Status   *int                 `json:"status"`
+	Detail   *string              `json:"detail,omitempty"`
+	Instance *string              `json:"instance,omitempty"`
+	Errors   []*ProblemField      `json:"errors,omitempty"`
+	TraceId  *string              `json:"traceId,omitempty"`
+	Items    []*ErrorResponseItem `json:"items,omitempty"`
+}
+
+type ProblemField struct {
+	Field *string `json:"field"`
+	Tag   *string `json:"tag"`
+	Param *string `json:"param,omitempty"`
+}
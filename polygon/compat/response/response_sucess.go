@@ -1,6 +1,8 @@
 package response
 
 import (
+	"encoding/json"
+
 	"github.com/bsthun/gut"
 	"go.scnd.dev/open/polygon"
 )
@@ -13,12 +15,63 @@ type SuccessResponse struct {
 }
 
 type GenericResponse[T any] struct {
-	Success *bool   `json:"success"`
-	Code    *string `json:"code,omitempty"`
-	Message *string `json:"message,omitempty"`
-	Data    T       `json:"data,omitempty"`
+	Success    *bool       `json:"success"`
+	Code       *string     `json:"code,omitempty"`
+	Message    *string     `json:"message,omitempty"`
+	Data       T           `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+type Pagination struct {
+	Limit  *int32 `json:"limit"`
+	Offset *int32 `json:"offset"`
+	Total  *int64 `json:"total,omitempty"`
 }
 
+type GenericBuilder[T any] struct {
+	Response *GenericResponse[T]
+	Span     polygon.Span
+}
+
+func Data[T any](s polygon.Span, data T) *GenericBuilder[T] {
+	return &GenericBuilder[T]{
+		Response: &GenericResponse[T]{
+			Success: gut.Ptr(true),
+			Data:    data,
+		},
+		Span: s,
+	}
+}
+
+func (r *GenericBuilder[T]) WithCode(code string) *GenericBuilder[T] {
+	r.Response.Code = &code
+	if r.Span != nil {
+		r.Span.Variable("response.code", code)
+	}
+	return r
+}
+
+func (r *GenericBuilder[T]) WithMessage(message string) *GenericBuilder[T] {
+	r.Response.Message = &message
+	return r
+}
+
+func (r *GenericBuilder[T]) WithPagination(paginate *Paginate, total int64) *GenericBuilder[T] {
+	r.Response.Pagination = &Pagination{
+		Total: &total,
+	}
+	if paginate != nil {
+		r.Response.Pagination.Limit = paginate.Limit
+		r.Response.Pagination.Offset = paginate.Offset
+	}
+	return r
+}
+
+func (r *GenericBuilder[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Response)
+}
+
+// Deprecated: use Data with WithCode and WithMessage instead.
 func Success(s polygon.Span, args1 any, args2 ...any) *SuccessResponse {
 	if message, ok := args1.(string); ok {
 		if len(args2) == 0 {
@@ -27,6 +80,9 @@ func Success(s polygon.Span, args1 any, args2 ...any) *SuccessResponse {
 				Message: &message,
 			}
 		}
+		if s != nil {
+			s.Variable("response.code", message)
+		}
 		if message2, ok := args2[0].(string); ok {
 			return &SuccessResponse{
 				Success: gut.Ptr(true),
@@ -37,7 +93,7 @@ func Success(s polygon.Span, args1 any, args2 ...any) *SuccessResponse {
 			return &SuccessResponse{
 				Success: gut.Ptr(true),
 				Code:    &message,
-				Data:    &message2,
+				Data:    args2[0],
 			}
 		}
 	}
@@ -0,0 +1,45 @@
+package response_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bsthun/gut"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/compat/response"
+	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestDataBuilder(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	s := recorder.Span(context.Background(), "root", "test", nil)
+	defer s.End()
+
+	cases := []struct {
+		builder  json.Marshaler
+		expected string
+	}{
+		{response.Data(nil, []int{1, 2}), `{"success":true,"data":[1,2]}`},
+		{response.Data(s, "ok").WithCode("USER_CREATED").WithMessage("created"), `{"success":true,"code":"USER_CREATED","message":"created","data":"ok"}`},
+		{response.Data(nil, []int{1}).WithPagination(&response.Paginate{Limit: gut.Ptr[int32](10), Offset: gut.Ptr[int32](20)}, 42), `{"success":true,"data":[1],"pagination":{"limit":10,"offset":20,"total":42}}`},
+		{response.Data(nil, []int{1}).WithPagination(nil, 1), `{"success":true,"data":[1],"pagination":{"limit":null,"offset":null,"total":1}}`},
+	}
+	for _, c := range cases {
+		raw, err := json.Marshal(c.builder)
+		if err != nil {
+			t.Fatalf("Failed to marshal response: %v", err)
+		}
+		if string(raw) != c.expected {
+			t.Errorf("Expected %s, got %s", c.expected, raw)
+		}
+	}
+
+	if code := s.(*span.Wrapper).Span.Variables["response.code"]; code != "USER_CREATED" {
+		t.Errorf("Expected response code on span, got %v", code)
+	}
+}
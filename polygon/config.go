@@ -1,10 +1,71 @@
 package polygon
 
+import (
+	"log/slog"
+)
+
 type Config struct {
 	AppName               *string
 	AppVersion            *string
 	AppNamespace          *string
 	AppInstanceId         *string
+	AppEnvironment        *Environment
+	TelemetryExporter     *TelemetryExporter
 	TelemetryUrl          *string
 	TelemetryOrganization *string
+	TelemetryFile         *string
+	TelemetrySampling     *TelemetrySampling
+	TelemetryPropagators  []TelemetryPropagator
+	TelemetryHttp         *TelemetryHttp
+	TelemetryRuntime      *bool
+	TelemetryRedact       func(key string) bool
+	LogFormat             *LogFormat
+	LogLevel              *slog.Level
+}
+
+type TelemetryExporter string
+
+const (
+	TelemetryExporterGrpc   TelemetryExporter = "grpc"
+	TelemetryExporterHttp   TelemetryExporter = "http"
+	TelemetryExporterStdout TelemetryExporter = "stdout"
+	TelemetryExporterFile   TelemetryExporter = "file"
+	TelemetryExporterNone   TelemetryExporter = "none"
+)
+
+type TelemetryPropagator string
+
+const (
+	TelemetryPropagatorTraceContext TelemetryPropagator = "tracecontext"
+	TelemetryPropagatorBaggage      TelemetryPropagator = "baggage"
+	TelemetryPropagatorB3           TelemetryPropagator = "b3"
+	TelemetryPropagatorB3Multi      TelemetryPropagator = "b3multi"
+	TelemetryPropagatorJaeger       TelemetryPropagator = "jaeger"
+)
+
+type TelemetrySampling struct {
+	Ratio     *float64
+	RateLimit *float64
+	Rules     []*TelemetrySamplingRule
 }
+
+type TelemetrySamplingRule struct {
+	Layer *string
+	Path  *string
+	Ratio *float64
+}
+
+type TelemetryHttp struct {
+	QueryParameters []string
+	RequestHeaders  []string
+	ResponseHeaders []string
+	Redact          []string
+}
+
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJson LogFormat = "json"
+	LogFormatOtlp LogFormat = "otlp"
+)
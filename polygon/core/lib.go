@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v3"
 	oteltrace "go.opentelemetry.io/otel/trace"
@@ -50,3 +51,15 @@ func (r *Instance) TracerMiddleware() fiber.Handler {
 func (r *Instance) Instrument() polygon.Instrument {
 	return r.telemetry.Instrument
 }
+
+func (r *Instance) Logger() *slog.Logger {
+	return r.telemetry.Logger
+}
+
+func (r *Instance) ForceFlush(context context.Context) error {
+	return r.telemetry.ForceFlush(context)
+}
+
+func (r *Instance) Shutdown(context context.Context) error {
+	return r.telemetry.Shutdown(context)
+}
@@ -0,0 +1,8 @@
+package polygon
+
+type Environment uint8
+
+var (
+	EnvironmentDevelopment Environment = 1
+	EnvironmentProduction  Environment = 2
+)
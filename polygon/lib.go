@@ -2,6 +2,7 @@ package polygon
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v3"
 	"go.opentelemetry.io/otel/trace"
@@ -12,5 +13,8 @@ type Polygon interface {
 	Tracer() trace.Tracer
 	TracerMiddleware() fiber.Handler
 	Instrument() Instrument
+	Logger() *slog.Logger
 	Span(context context.Context, name, layer string, arguments map[string]any) Span
+	ForceFlush(context context.Context) error
+	Shutdown(context context.Context) error
 }
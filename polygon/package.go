@@ -2,7 +2,10 @@ package polygon
 
 import (
 	"context"
+	"log/slog"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Span interface {
@@ -10,12 +13,49 @@ type Span interface {
 	SetContext(context context.Context)
 	Started() *time.Time
 	Error(message string, err error) error
+	ErrorDimension(dimensionType DimensionType, dimensionScope DimensionScope, message string, err error) error
+	ErrorStatus(status int, message string, err error) error
 	Variable(key string, value any)
+	Log(level slog.Level, message string, kv ...any)
+	Event(name string, attributes ...attribute.KeyValue)
+	Link(context context.Context, attributes ...attribute.KeyValue)
+	Baggage(key string) string
+	SetBaggage(key string, value string) error
 	Fork(layer string) Span
 	End()
 }
 
 type Instrument interface {
-	HttpDurationRecord(ctx context.Context, duration int64, path string, status int)
-	HttpActiveRequestCounter(ctx context.Context, delta int64, path string)
+	HttpDurationRecord(ctx context.Context, duration int64, method string, route string, status int)
+	HttpActiveRequestCounter(ctx context.Context, delta int64, method string)
+	HttpClientDurationRecord(ctx context.Context, duration int64, method string, host string, status int)
+	ErrorCountRecord(ctx context.Context, dimensionType DimensionType, dimensionScope DimensionScope, layer string)
+	Counter(definition *InstrumentDefinition) (Counter, error)
+	UpDownCounter(definition *InstrumentDefinition) (UpDownCounter, error)
+	Histogram(definition *InstrumentDefinition) (Histogram, error)
+	Gauge(definition *InstrumentDefinition, callback GaugeCallback) error
+}
+
+type InstrumentDefinition struct {
+	Name        *string
+	Description *string
+	Unit        *string
+	Buckets     []float64
+	Attributes  []string
 }
+
+type Counter interface {
+	Add(ctx context.Context, value int64, attributes ...attribute.KeyValue) error
+}
+
+type UpDownCounter interface {
+	Add(ctx context.Context, value int64, attributes ...attribute.KeyValue) error
+}
+
+type Histogram interface {
+	Record(ctx context.Context, value float64, attributes ...attribute.KeyValue) error
+}
+
+type GaugeObserver func(value float64, attributes ...attribute.KeyValue) error
+
+type GaugeCallback func(ctx context.Context, observe GaugeObserver) error
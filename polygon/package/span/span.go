@@ -3,43 +3,174 @@ package span
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
 )
 
 type Span struct {
 	Name           *string         `json:"name,omitempty"`
 	Layer          *string         `json:"layer,omitempty"`
 	Context        *Context        `json:"context,omitempty"`
+	Parent         *Span           `json:"-"`
 	Caller         *Caller         `json:"caller,omitempty"`
 	Variables      map[string]any  `json:"variables,omitempty"`
 	Started        *time.Time      `json:"started,omitempty"`
 	Ended          *time.Time      `json:"ended,omitempty"`
 	TracingSpan    trace.Span      `json:"-"`
 	TracingContext context.Context `json:"-"`
+	Mutex          sync.RWMutex    `json:"-"`
 }
 
 func (r *Span) Variable(key string, value any) {
+	// * redact sensitive value and nested fields
+	config := r.Context.Polygon.Config()
+	if config.Redacted(key) {
+		value = polygon.Redacted
+	} else {
+		value = Redact(config.Redacted, value)
+	}
+
+	r.Mutex.Lock()
 	r.Variables[key] = value
-	r.TracingSpan.SetAttributes(attribute.String(fmt.Sprintf("var.%s", key), fmt.Sprintf("%v", value)))
+	r.Mutex.Unlock()
+	r.TracingSpan.SetAttributes(Attribute(fmt.Sprintf("var.%s", key), value))
+}
+
+func (r *Span) Log(level slog.Level, message string, kv ...any) {
+	logger := r.Context.Polygon.Logger()
+	tracingContext := r.Current()
+	if !logger.Enabled(tracingContext, level) {
+		return
+	}
+
+	// * construct span attributes
+	spanContext := r.TracingSpan.SpanContext()
+	attributes := []any{
+		slog.String("span.name", r.CurrentName()),
+		slog.String("span.layer", *r.Layer),
+		slog.String("span.caller", r.Caller.String()),
+		slog.String("trace_id", spanContext.TraceID().String()),
+		slog.String("span_id", spanContext.SpanID().String()),
+	}
+
+	// * construct variable attributes
+	r.Mutex.RLock()
+	if len(r.Variables) > 0 {
+		keys := make([]string, 0, len(r.Variables))
+		for key := range r.Variables {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		variables := make([]any, 0, len(keys))
+		for _, key := range keys {
+			variables = append(variables, slog.Any(key, r.Variables[key]))
+		}
+		attributes = append(attributes, slog.Group("var", variables...))
+	}
+	r.Mutex.RUnlock()
+
+	logger.Log(tracingContext, level, message, append(attributes, kv...)...)
+}
+
+func (r *Span) Event(name string, attributes ...attribute.KeyValue) {
+	r.TracingSpan.AddEvent(name, trace.WithAttributes(attributes...))
+}
+
+func (r *Span) Link(ctx context.Context, attributes ...attribute.KeyValue) {
+	r.TracingSpan.AddLink(trace.LinkFromContext(ctx, attributes...))
+}
+
+func (r *Span) Baggage(key string) string {
+	return baggage.FromContext(r.Current()).Member(key).Value()
+}
+
+func (r *Span) SetBaggage(key string, value string) error {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return NewError(r, "invalid baggage member", err)
+	}
+
+	// * replace tracing context with updated baggage
+	r.Mutex.Lock()
+	bag, err := baggage.FromContext(r.TracingContext).SetMember(member)
+	if err == nil {
+		r.TracingContext = baggage.ContextWithBaggage(r.TracingContext, bag)
+	}
+	r.Mutex.Unlock()
+
+	if err != nil {
+		return NewError(r, "unable to set baggage", err)
+	}
+	return nil
+}
+
+func (r *Span) Current() context.Context {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
+	return r.TracingContext
+}
+
+func (r *Span) SetCurrent(ctx context.Context) {
+	r.Context.SetParent(ctx)
+
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	// * carry baggage set earlier, members on the new context take precedence
+	bag := baggage.FromContext(ctx)
+	for _, member := range baggage.FromContext(r.TracingContext).Members() {
+		if bag.Member(member.Key()).Key() == "" {
+			if merged, err := bag.SetMember(member); err == nil {
+				bag = merged
+			}
+		}
+	}
+	if bag.Len() > 0 {
+		ctx = baggage.ContextWithBaggage(ctx, bag)
+	}
+	r.TracingContext = trace.ContextWithSpan(ctx, r.TracingSpan)
+}
+
+func (r *Span) CurrentName() string {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
+	return *r.Name
+}
+
+func (r *Span) SetName(name string) {
+	r.Mutex.Lock()
+	r.Name = &name
+	r.Mutex.Unlock()
+	r.TracingSpan.SetName(name)
 }
 
 func (r *Span) Fork(layer string) *Span {
-	caller := NewCaller(2)
+	return r.ForkCaller(layer, NewCaller(2))
+}
+
+func (r *Span) ForkCaller(layer string, caller *Caller) *Span {
 	now := time.Now()
 	traceStr := caller.String()
-	name := fmt.Sprintf("%s/%s", *r.Name, traceStr)
+	name := fmt.Sprintf("%s/%s", r.CurrentName(), traceStr)
 
-	tracingContext, tracingSpan := r.Context.Polygon.Tracer().Start(r.Context, name)
-	tracingSpan.SetAttributes(attribute.String("span.layer", layer))
-	tracingSpan.SetAttributes(attribute.String("span.caller", caller.String()))
+	tracingContext, tracingSpan := r.Context.Polygon.Tracer().Start(r.Current(), name, trace.WithAttributes(
+		attribute.String("span.layer", layer),
+		attribute.String("span.caller", caller.String()),
+	))
 
 	d2 := &Span{
 		Name:           &name,
 		Layer:          &layer,
 		Context:        r.Context,
+		Parent:         r,
 		Caller:         caller,
 		Variables:      make(map[string]any),
 		Started:        &now,
@@ -48,7 +179,7 @@ func (r *Span) Fork(layer string) *Span {
 		TracingContext: tracingContext,
 	}
 
-	r.Context.Spans = append(r.Context.Spans, d2)
+	r.Context.Register(d2)
 	return d2
 }
 
@@ -56,12 +187,33 @@ func (r *Span) Error(message string, err error) error {
 	return NewError(r, message, err)
 }
 
+func (r *Span) ErrorDimension(dimensionType DimensionType, dimensionScope DimensionScope, message string, err error) error {
+	return NewErrorDimension(r, dimensionType, dimensionScope, message, err)
+}
+
+func (r *Span) ErrorStatus(status int, message string, err error) error {
+	return NewErrorStatus(r, status, message, err)
+}
+
+func (r *Span) Ancestors(outer *Span) []*Span {
+	ancestors := make([]*Span, 0)
+	for current := r; current != outer; current = current.Parent {
+		if current == nil {
+			return nil
+		}
+		ancestors = append(ancestors, current)
+	}
+	return ancestors
+}
+
 func (r *Span) Tracing() trace.Span {
 	return r.TracingSpan
 }
 
 func (r *Span) End() {
 	end := time.Now()
+	r.Mutex.Lock()
 	r.Ended = &end
+	r.Mutex.Unlock()
 	r.TracingSpan.End()
 }
@@ -0,0 +1,182 @@
+package span
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.scnd.dev/open/polygon"
+)
+
+const AttributeLimit = 4096
+
+func Attribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case nil:
+		return attribute.String(key, "<nil>")
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int64(key, int64(v))
+	case int16:
+		return attribute.Int64(key, int64(v))
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint8:
+		return attribute.Int64(key, int64(v))
+	case uint16:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case time.Duration:
+		return attribute.Float64(key, v.Seconds())
+	case time.Time:
+		return attribute.String(key, v.Format(time.RFC3339Nano))
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	}
+
+	// * resolve remaining kinds by reflection
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Pointer:
+		if reflected.IsNil() {
+			return attribute.String(key, "<nil>")
+		}
+		return Attribute(key, reflected.Elem().Interface())
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if number := reflected.Uint(); number <= 1<<63-1 {
+			return attribute.Int64(key, int64(number))
+		}
+		return attribute.String(key, fmt.Sprintf("%v", value))
+	case reflect.Slice, reflect.Array:
+		return AttributeSlice(key, reflected)
+	case reflect.Struct, reflect.Map:
+		return AttributeJson(key, value)
+	default:
+		return attribute.String(key, fmt.Sprintf("%v", value))
+	}
+}
+
+func AttributeSlice(key string, reflected reflect.Value) attribute.KeyValue {
+	switch reflected.Type().Elem().Kind() {
+	case reflect.String:
+		values := make([]string, reflected.Len())
+		for i := range values {
+			values[i] = reflected.Index(i).String()
+		}
+		return attribute.StringSlice(key, values)
+	case reflect.Bool:
+		values := make([]bool, reflected.Len())
+		for i := range values {
+			values[i] = reflected.Index(i).Bool()
+		}
+		return attribute.BoolSlice(key, values)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		values := make([]int64, reflected.Len())
+		for i := range values {
+			values[i] = reflected.Index(i).Int()
+		}
+		return attribute.Int64Slice(key, values)
+	case reflect.Float32, reflect.Float64:
+		values := make([]float64, reflected.Len())
+		for i := range values {
+			values[i] = reflected.Index(i).Float()
+		}
+		return attribute.Float64Slice(key, values)
+	default:
+		return AttributeJson(key, reflected.Interface())
+	}
+}
+
+func AttributeJson(key string, value any) attribute.KeyValue {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return attribute.String(key, fmt.Sprintf("%v", value))
+	}
+
+	// * truncate oversized payload on rune boundary
+	if len(encoded) > AttributeLimit {
+		limit := AttributeLimit
+		for limit > 0 && !utf8.RuneStart(encoded[limit]) {
+			limit--
+		}
+		return attribute.String(key, string(encoded[:limit])+"...")
+	}
+	return attribute.String(key, string(encoded))
+}
+
+func Redact(redacted func(key string) bool, value any) any {
+	if value == nil {
+		return value
+	}
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return value
+	}
+
+	// * decode into generic form to inspect nested field names
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return value
+	}
+
+	// * keep original value when nothing is sensitive
+	if !RedactNested(redacted, generic) {
+		return value
+	}
+	return generic
+}
+
+func RedactNested(redacted func(key string) bool, node any) bool {
+	changed := false
+	switch v := node.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if redacted(key) {
+				v[key] = polygon.Redacted
+				changed = true
+				continue
+			}
+			changed = RedactNested(redacted, item) || changed
+		}
+	case []any:
+		for _, item := range v {
+			changed = RedactNested(redacted, item) || changed
+		}
+	}
+	return changed
+}
@@ -2,9 +2,12 @@ package span
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.scnd.dev/open/polygon"
 )
 
@@ -14,9 +17,10 @@ type Context struct {
 	Type      *string
 	Arguments map[string]any
 	Spans     []*Span
+	Mutex     sync.Mutex
 }
 
-func NewContext(polygon polygon.Polygon, context context.Context, name string, layer string, arguments map[string]any) *Span {
+func NewContext(polygon polygon.Polygon, context context.Context, name string, layer string, arguments map[string]any, options ...trace.SpanStartOption) *Span {
 	c := &Context{
 		Polygon:   polygon,
 		Context:   context,
@@ -28,14 +32,17 @@ func NewContext(polygon polygon.Polygon, context context.Context, name string, l
 	caller := NewCaller(2)
 	now := time.Now()
 
-	tracingContext, tracingSpan := polygon.Tracer().Start(context, name)
-	tracingSpan.SetAttributes(attribute.String("span.layer", layer))
-	tracingSpan.SetAttributes(attribute.String("span.caller", caller.String()))
+	options = append(options, trace.WithAttributes(
+		attribute.String("span.layer", layer),
+		attribute.String("span.caller", caller.String()),
+	))
+	tracingContext, tracingSpan := polygon.Tracer().Start(context, name, options...)
 
 	s := &Span{
 		Name:           &name,
 		Layer:          &layer,
 		Context:        c,
+		Parent:         nil,
 		Caller:         caller,
 		Variables:      make(map[string]any),
 		Started:        &now,
@@ -43,23 +50,47 @@ func NewContext(polygon polygon.Polygon, context context.Context, name string, l
 		TracingSpan:    tracingSpan,
 		TracingContext: tracingContext,
 	}
-	c.Spans = append(c.Spans, s)
+	c.Register(s)
 
 	return s
 }
 
+func (r *Context) Register(span *Span) {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	r.Spans = append(r.Spans, span)
+}
+
+func (r *Context) Snapshot() []*Span {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	return slices.Clone(r.Spans)
+}
+
+func (r *Context) Parent() context.Context {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	return r.Context
+}
+
+func (r *Context) SetParent(ctx context.Context) {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	r.Context = ctx
+}
+
 func (r *Context) Deadline() (deadline time.Time, ok bool) {
-	return r.Context.Deadline()
+	return r.Parent().Deadline()
 }
 
 func (r *Context) Done() <-chan struct{} {
-	return r.Context.Done()
+	return r.Parent().Done()
 }
 
 func (r *Context) Err() error {
-	return r.Context.Err()
+	return r.Parent().Err()
 }
 
 func (r *Context) Value(key any) any {
-	return r.Context.Value(key)
+	return r.Parent().Value(key)
 }
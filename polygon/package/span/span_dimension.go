@@ -1,27 +1,31 @@
 package span
 
-type DimensionType string
+import (
+	"go.scnd.dev/open/polygon"
+)
+
+type DimensionType = polygon.DimensionType
 
 const (
-	DimensionTypeTimeout    DimensionType = "timeout"
-	DimensionTypeValidation DimensionType = "validation"
-	DimensionTypeFatal      DimensionType = "fatal"
-	DimensionTypeOperation  DimensionType = "operation"
-	DimensionTypeOverflow   DimensionType = "overflow"
+	DimensionTypeTimeout    = polygon.DimensionTypeTimeout
+	DimensionTypeValidation = polygon.DimensionTypeValidation
+	DimensionTypeFatal      = polygon.DimensionTypeFatal
+	DimensionTypeOperation  = polygon.DimensionTypeOperation
+	DimensionTypeOverflow   = polygon.DimensionTypeOverflow
 )
 
-type DimensionScope string
+type DimensionScope = polygon.DimensionScope
 
 const (
-	DimensionScopeConfig    DimensionScope = "config"
-	DimensionScopeLibrary   DimensionScope = "library"
-	DimensionScopeDatabase  DimensionScope = "database"
-	DimensionScopeProcedure DimensionScope = "procedure"
-	DimensionScopeExternal  DimensionScope = "external"
+	DimensionScopeConfig    = polygon.DimensionScopeConfig
+	DimensionScopeLibrary   = polygon.DimensionScopeLibrary
+	DimensionScopeDatabase  = polygon.DimensionScopeDatabase
+	DimensionScopeProcedure = polygon.DimensionScopeProcedure
+	DimensionScopeExternal  = polygon.DimensionScopeExternal
 )
 
-type DimensionPlace string
+type DimensionPlace = polygon.DimensionPlace
 
 const (
-	DimensionPlacePolygon DimensionPlace = "polygon"
+	DimensionPlacePolygon = polygon.DimensionPlacePolygon
 )
@@ -2,57 +2,133 @@ package span
 
 import (
 	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Error struct {
 	Items []*ErrorItem `json:"items,omitempty"`
 }
 
+// Error joins the messages from the outermost item to the root cause so the
+// text names every failing layer, not only the wrapped error.
 func (r *Error) Error() string {
-	return r.Items[len(r.Items)-1].Error.Error()
+	// * join messages from outermost to innermost
+	messages := make([]string, 0, len(r.Items)+1)
+	for i := len(r.Items) - 1; i >= 0; i-- {
+		if r.Items[i].Message != nil {
+			messages = append(messages, *r.Items[i].Message)
+		}
+	}
+
+	// * append root cause
+	if r.Items[0].Error != nil {
+		messages = append(messages, r.Items[0].Error.Error())
+	}
+
+	return strings.Join(messages, ": ")
+}
+
+// Unwrap exposes the root cause to errors.Is and errors.As, such as sentinel
+// statuses and exporter errors joined by Shutdown.
+func (r *Error) Unwrap() error {
+	return r.Items[0].Error
 }
 
 type ErrorItem struct {
-	Span    *Span   `json:"type,omitempty"`
-	Trace   *Caller `json:"trace,omitempty"`
-	Message *string `json:"message,omitempty"`
-	Error   error   `json:"error,omitempty"`
+	Span      *Span           `json:"type,omitempty"`
+	Trace     *Caller         `json:"trace,omitempty"`
+	Message   *string         `json:"message,omitempty"`
+	Error     error           `json:"error,omitempty"`
+	Dimension *DimensionType  `json:"dimension,omitempty"`
+	Scope     *DimensionScope `json:"scope,omitempty"`
+	Status    *int            `json:"status,omitempty"`
+}
+
+func (r *Error) Record() {
+	item := r.Items[len(r.Items)-1]
+	if item.Span == nil {
+		return
+	}
+
+	// * mark tracing span
+	item.Span.TracingSpan.RecordError(r, trace.WithAttributes(
+		attribute.String("span.caller", item.Trace.String()),
+	))
+	item.Span.TracingSpan.SetStatus(codes.Error, *item.Message)
+
+	// * propagate status to intermediate spans
+	if len(r.Items) > 1 && r.Items[len(r.Items)-2].Span != nil {
+		for _, ancestor := range r.Items[len(r.Items)-2].Span.Parent.Ancestors(item.Span) {
+			ancestor.TracingSpan.SetStatus(codes.Error, *item.Message)
+		}
+	}
+
+	// * classify error
+	if item.Dimension != nil {
+		item.Span.TracingSpan.SetAttributes(
+			semconv.ErrorTypeKey.String(string(*item.Dimension)),
+			attribute.String("error.scope", string(*item.Scope)),
+		)
+		item.Span.Context.Polygon.Instrument().ErrorCountRecord(item.Span.Current(), *item.Dimension, *item.Scope, *item.Span.Layer)
+	}
 }
 
 func NewError(span *Span, message string, err error) error {
-	trace := NewCaller(2)
+	e := WrapError(&ErrorItem{
+		Span:    span,
+		Trace:   NewCaller(2),
+		Message: &message,
+		Error:   nil,
+	}, err)
+	e.Record()
+	return e
+}
+
+func NewErrorDimension(span *Span, dimensionType DimensionType, dimensionScope DimensionScope, message string, err error) error {
+	e := WrapError(&ErrorItem{
+		Span:      span,
+		Trace:     NewCaller(2),
+		Message:   &message,
+		Error:     nil,
+		Dimension: &dimensionType,
+		Scope:     &dimensionScope,
+	}, err)
+	e.Record()
+	return e
+}
+
+func NewErrorStatus(span *Span, status int, message string, err error) error {
+	e := WrapError(&ErrorItem{
+		Span:    span,
+		Trace:   NewCaller(2),
+		Message: &message,
+		Error:   nil,
+		Status:  &status,
+	}, err)
+	e.Record()
+	return e
+}
+
+func WrapError(item *ErrorItem, err error) *Error {
 	if err == nil {
 		return &Error{
-			Items: []*ErrorItem{
-				{
-					Span:    span,
-					Trace:   trace,
-					Message: &message,
-					Error:   nil,
-				},
-			},
+			Items: []*ErrorItem{item},
 		}
 	}
 
 	var e *Error
 	if errors.As(err, &e) {
-		e.Items = append(e.Items, &ErrorItem{
-			Span:    span,
-			Trace:   trace,
-			Message: &message,
-			Error:   nil,
-		})
+		e.Items = append(e.Items, item)
 		return e
 	}
 
+	item.Error = err
 	return &Error{
-		Items: []*ErrorItem{
-			{
-				Span:    span,
-				Trace:   trace,
-				Message: &message,
-				Error:   err,
-			},
-		},
+		Items: []*ErrorItem{item},
 	}
 }
@@ -0,0 +1,46 @@
+package span
+
+import (
+	"sync"
+
+	"go.scnd.dev/open/polygon"
+)
+
+type Group struct {
+	Parent    polygon.Span
+	WaitGroup sync.WaitGroup
+	Once      sync.Once
+	Err       error
+}
+
+func NewGroup(parent polygon.Span) *Group {
+	return &Group{
+		Parent: parent,
+	}
+}
+
+func (r *Group) Go(layer string, fn func(s polygon.Span) error) {
+	// * fork in caller goroutine to keep parent and caller
+	var child polygon.Span
+	if wrapper, ok := r.Parent.(*Wrapper); ok {
+		child = &Wrapper{
+			Span: wrapper.Span.ForkCaller(layer, NewCaller(1)),
+		}
+	} else {
+		child = r.Parent.Fork(layer)
+	}
+
+	r.WaitGroup.Go(func() {
+		defer child.End()
+		if err := fn(child); err != nil {
+			r.Once.Do(func() {
+				r.Err = err
+			})
+		}
+	})
+}
+
+func (r *Group) Wait() error {
+	r.WaitGroup.Wait()
+	return r.Err
+}
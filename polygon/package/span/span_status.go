@@ -0,0 +1,69 @@
+package span
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+type Sentinel struct {
+	Error  error
+	Status int
+}
+
+// SentinelStatus is checked in order, so the first matching sentinel wins
+// when an error wraps several of them.
+var SentinelStatus = []Sentinel{
+	{Error: ErrNotFound, Status: http.StatusNotFound},
+	{Error: ErrConflict, Status: http.StatusConflict},
+	{Error: ErrUnauthorized, Status: http.StatusUnauthorized},
+	{Error: ErrForbidden, Status: http.StatusForbidden},
+}
+
+var DimensionStatus = map[DimensionType]int{
+	DimensionTypeValidation: http.StatusBadRequest,
+	DimensionTypeOperation:  http.StatusInternalServerError,
+	DimensionTypeOverflow:   http.StatusTooManyRequests,
+	DimensionTypeFatal:      http.StatusInternalServerError,
+	DimensionTypeTimeout:    http.StatusGatewayTimeout,
+}
+
+func (r *Error) Status() int {
+	// * resolve from outermost item
+	for i := len(r.Items) - 1; i >= 0; i-- {
+		item := r.Items[i]
+		if item.Status != nil {
+			return *item.Status
+		}
+		if item.Dimension != nil {
+			if status, ok := DimensionStatus[*item.Dimension]; ok {
+				return status
+			}
+		}
+	}
+
+	// * resolve from sentinel cause
+	if status, ok := ErrorSentinelStatus(r.Items[0].Error); ok {
+		return status
+	}
+
+	return http.StatusBadRequest
+}
+
+func ErrorSentinelStatus(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+	for _, sentinel := range SentinelStatus {
+		if errors.Is(err, sentinel.Error) {
+			return sentinel.Status, true
+		}
+	}
+	return 0, false
+}
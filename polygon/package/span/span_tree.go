@@ -0,0 +1,179 @@
+package span
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+)
+
+type Tree struct {
+	Name       *string        `json:"name,omitempty"`
+	Layer      *string        `json:"layer,omitempty"`
+	Caller     *string        `json:"caller,omitempty"`
+	Variables  map[string]any `json:"variables,omitempty"`
+	Started    *time.Time     `json:"started,omitempty"`
+	Ended      *time.Time     `json:"ended,omitempty"`
+	DurationMs *float64       `json:"durationMs,omitempty"`
+	Children   []*Tree        `json:"children,omitempty"`
+	Truncated  *bool          `json:"truncated,omitempty"`
+}
+
+func (r *Context) Tree() *Tree {
+	spans := r.Snapshot()
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// * construct nodes
+	now := time.Now()
+	nodes := make(map[*Span]*Tree, len(spans))
+	for _, span := range spans {
+		nodes[span] = span.Tree(now)
+	}
+
+	// * link children in registration order
+	var root *Tree
+	for _, span := range spans {
+		parent, ok := nodes[span.Parent]
+		if !ok {
+			if root == nil {
+				root = nodes[span]
+			}
+			continue
+		}
+		parent.Children = append(parent.Children, nodes[span])
+	}
+
+	return root
+}
+
+func (r *Span) Tree(now time.Time) *Tree {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
+
+	caller := r.Caller.String()
+	end := now
+	if r.Ended != nil {
+		end = *r.Ended
+	}
+	duration := float64(end.Sub(*r.Started).Microseconds()) / 1000
+
+	return &Tree{
+		Name:       r.Name,
+		Layer:      r.Layer,
+		Caller:     &caller,
+		Variables:  maps.Clone(r.Variables),
+		Started:    r.Started,
+		Ended:      r.Ended,
+		DurationMs: &duration,
+		Children:   make([]*Tree, 0),
+	}
+}
+
+func (r *Tree) Json() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *Tree) Depth() int {
+	depth := 0
+	r.Walk(func(tree *Tree, d int) {
+		depth = max(depth, d)
+	})
+	return depth
+}
+
+func (r *Tree) Prune(depth int) *Tree {
+	if r == nil {
+		return nil
+	}
+
+	// * copy node and cut children below depth
+	pruned := *r
+	pruned.Children = make([]*Tree, 0, len(r.Children))
+	if depth <= 0 {
+		if len(r.Children) > 0 {
+			truncated := true
+			pruned.Truncated = &truncated
+		}
+		return &pruned
+	}
+	for _, child := range r.Children {
+		pruned.Children = append(pruned.Children, child.Prune(depth-1))
+	}
+	return &pruned
+}
+
+func (r *Tree) Waterfall(width int) string {
+	if r == nil {
+		return ""
+	}
+
+	// * collect rows
+	type row struct {
+		label string
+		tree  *Tree
+	}
+	rows := make([]row, 0)
+	labelWidth := 0
+	r.Walk(func(tree *Tree, depth int) {
+		label := fmt.Sprintf("%s%s %s", strings.Repeat("  ", depth), *tree.Layer, *tree.Caller)
+		labelWidth = max(labelWidth, len(label))
+		rows = append(rows, row{label: label, tree: tree})
+	})
+
+	// * render bars relative to root window
+	width = max(width, 1)
+	window := max(*r.DurationMs, 0.001)
+	builder := new(strings.Builder)
+	for _, row := range rows {
+		offset := float64(row.tree.Started.Sub(*r.Started).Microseconds()) / 1000
+		start := min(width-1, int(offset/window*float64(width)))
+		length := max(1, min(width-start, int(*row.tree.DurationMs/window*float64(width))))
+		bar := strings.Repeat(" ", start) + strings.Repeat("=", length) + strings.Repeat(" ", width-start-length)
+		_, _ = fmt.Fprintf(builder, "%-*s |%s| %.3fms\n", labelWidth, row.label, bar, *row.tree.DurationMs)
+	}
+
+	return builder.String()
+}
+
+func (r *Tree) Flamegraph(writer io.Writer) error {
+	if r == nil {
+		return nil
+	}
+
+	var err error
+	stack := make([]string, 0)
+	r.Walk(func(tree *Tree, depth int) {
+		if err != nil {
+			return
+		}
+
+		// * maintain frame stack
+		stack = append(stack[:depth], strings.ReplaceAll(fmt.Sprintf("%s %s", *tree.Layer, *tree.Caller), ";", ":"))
+
+		// * compute self time in microseconds
+		self := *tree.DurationMs
+		for _, child := range tree.Children {
+			self -= *child.DurationMs
+		}
+		_, err = fmt.Fprintf(writer, "%s %d\n", strings.Join(stack, ";"), int64(max(self, 0)*1000))
+	})
+	return err
+}
+
+func (r *Tree) Walk(fn func(tree *Tree, depth int)) {
+	if r == nil {
+		return
+	}
+	r.walk(fn, 0)
+}
+
+func (r *Tree) walk(fn func(tree *Tree, depth int), depth int) {
+	fn(r, depth)
+	for _, child := range r.Children {
+		child.walk(fn, depth+1)
+	}
+}
@@ -2,8 +2,10 @@ package span
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.scnd.dev/open/polygon"
 )
 
@@ -12,11 +14,11 @@ type Wrapper struct {
 }
 
 func (r *Wrapper) Context() context.Context {
-	return r.Span.Context
+	return r.Span.Current()
 }
 
 func (r *Wrapper) SetContext(context context.Context) {
-	r.Span.Context.Context = context
+	r.Span.SetCurrent(context)
 }
 
 func (r *Wrapper) Started() *time.Time {
@@ -27,6 +29,26 @@ func (r *Wrapper) Variable(key string, value any) {
 	r.Span.Variable(key, value)
 }
 
+func (r *Wrapper) Log(level slog.Level, message string, kv ...any) {
+	r.Span.Log(level, message, kv...)
+}
+
+func (r *Wrapper) Event(name string, attributes ...attribute.KeyValue) {
+	r.Span.Event(name, attributes...)
+}
+
+func (r *Wrapper) Link(context context.Context, attributes ...attribute.KeyValue) {
+	r.Span.Link(context, attributes...)
+}
+
+func (r *Wrapper) Baggage(key string) string {
+	return r.Span.Baggage(key)
+}
+
+func (r *Wrapper) SetBaggage(key string, value string) error {
+	return r.Span.SetBaggage(key, value)
+}
+
 func (r *Wrapper) Fork(layer string) polygon.Span {
 	return &Wrapper{
 		Span: r.Span.Fork(layer),
@@ -34,7 +56,15 @@ func (r *Wrapper) Fork(layer string) polygon.Span {
 }
 
 func (r *Wrapper) Error(message string, err error) error {
-	return r.Span.Error(message, err)
+	return NewError(r.Span, message, err)
+}
+
+func (r *Wrapper) ErrorDimension(dimensionType polygon.DimensionType, dimensionScope polygon.DimensionScope, message string, err error) error {
+	return NewErrorDimension(r.Span, dimensionType, dimensionScope, message, err)
+}
+
+func (r *Wrapper) ErrorStatus(status int, message string, err error) error {
+	return NewErrorStatus(r.Span, status, message, err)
 }
 
 func (r *Wrapper) End() {
@@ -0,0 +1,581 @@
+package span_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/core"
+	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func newPolygon(t *testing.T) polygon.Polygon {
+	exporter := polygon.TelemetryExporterNone
+	level := slog.LevelWarn
+	p, err := core.New(&polygon.Config{
+		TelemetryExporter: &exporter,
+		LogLevel:          &level,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create polygon: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = p.Shutdown(context.Background())
+	})
+	return p
+}
+
+func TestSpanConcurrentFork(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	// Fork, annotate and fail spans from many goroutines at once
+	count := 64
+	var wg sync.WaitGroup
+	for i := range count {
+		wg.Go(func() {
+			child := root.Fork("worker")
+			defer child.End()
+			child.Variable("index", i)
+			root.Variable(fmt.Sprintf("worker.%d", i), true)
+			child.Log(slog.LevelInfo, "working")
+			_ = child.Error("worker failed", errors.New("boom"))
+		})
+	}
+	wg.Wait()
+
+	rootSpan := root.(*span.Wrapper).Span
+	spans := rootSpan.Context.Snapshot()
+	if len(spans) != count+1 {
+		t.Fatalf("Expected %d spans, got %d", count+1, len(spans))
+	}
+
+	for _, s := range spans[1:] {
+		if s.Parent != rootSpan {
+			t.Errorf("Expected span %s to be a child of root", *s.Name)
+		}
+		if s.Ended == nil {
+			t.Errorf("Expected span %s to be ended", *s.Name)
+		}
+	}
+
+	if len(rootSpan.Variables) != count {
+		t.Errorf("Expected %d root variables, got %d", count, len(rootSpan.Variables))
+	}
+}
+
+func TestGroupPreservesParent(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	// Fan out twice so that nested groups fork from their own parent
+	group := span.NewGroup(root)
+	for i := range 8 {
+		group.Go("service", func(s polygon.Span) error {
+			nested := span.NewGroup(s)
+			for j := range 4 {
+				nested.Go("database", func(d polygon.Span) error {
+					d.Variable("query", j)
+					return nil
+				})
+			}
+			if err := nested.Wait(); err != nil {
+				return err
+			}
+			if i == 3 {
+				return s.Error("service failed", nil)
+			}
+			return nil
+		})
+	}
+
+	err := group.Wait()
+	if err == nil {
+		t.Fatal("Expected group error")
+	}
+
+	var spanError *span.Error
+	if !errors.As(err, &spanError) || *spanError.Items[0].Message != "service failed" {
+		t.Errorf("Expected service failure, got %v", err)
+	}
+
+	rootSpan := root.(*span.Wrapper).Span
+	spans := rootSpan.Context.Snapshot()
+	if len(spans) != 1+8+8*4 {
+		t.Fatalf("Expected %d spans, got %d", 1+8+8*4, len(spans))
+	}
+
+	for _, s := range spans[1:] {
+		switch *s.Layer {
+		case "service":
+			if s.Parent != rootSpan {
+				t.Errorf("Expected service span %s to be a child of root", *s.Name)
+			}
+		case "database":
+			if s.Parent == nil || *s.Parent.Layer != "service" {
+				t.Errorf("Expected database span %s to be a child of service", *s.Name)
+			}
+		}
+		if s.Ended == nil {
+			t.Errorf("Expected span %s to be ended", *s.Name)
+		}
+	}
+}
+
+func TestContextTree(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+
+	// Build root -> service -> database
+	service := root.Fork("service")
+	database := service.Fork("database")
+	database.Variable("query", "select")
+	database.End()
+	service.End()
+	root.End()
+
+	tree := root.(*span.Wrapper).Span.Context.Tree()
+	if *tree.Layer != "test" || len(tree.Children) != 1 {
+		t.Fatalf("Expected root with one child, got %+v", tree)
+	}
+	if child := tree.Children[0]; *child.Layer != "service" || len(child.Children) != 1 || *child.Children[0].Layer != "database" {
+		t.Fatalf("Expected service with database child, got %+v", child)
+	}
+
+	if _, err := tree.Json(); err != nil {
+		t.Errorf("Failed to encode tree: %v", err)
+	}
+
+	waterfall := tree.Waterfall(40)
+	if lines := strings.Split(strings.TrimSpace(waterfall), "\n"); len(lines) != 3 {
+		t.Errorf("Expected 3 waterfall lines, got %q", waterfall)
+	}
+	for _, width := range []int{0, -1} {
+		if lines := strings.Split(strings.TrimSpace(tree.Waterfall(width)), "\n"); len(lines) != 3 || !strings.Contains(lines[0], "|=|") {
+			t.Errorf("Expected clamped waterfall for width %d, got %q", width, lines)
+		}
+	}
+
+	builder := new(strings.Builder)
+	if err := tree.Flamegraph(builder); err != nil {
+		t.Fatalf("Failed to write flamegraph: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+	if len(lines) != 3 || strings.Count(lines[2], ";") != 2 || !strings.HasPrefix(lines[2], "test ") {
+		t.Errorf("Expected nested collapsed stacks, got %q", builder.String())
+	}
+
+	// Pruning cuts deeper levels and marks where children were dropped
+	pruned := tree.Prune(1)
+	if tree.Depth() != 2 || pruned.Depth() != 1 || pruned.Truncated != nil || pruned.Children[0].Truncated == nil || !*pruned.Children[0].Truncated {
+		t.Errorf("Expected tree pruned below service, got %+v", pruned.Children[0])
+	}
+	if len(tree.Children[0].Children) != 1 {
+		t.Error("Expected pruning to leave the original tree intact")
+	}
+
+	// Empty contexts render nothing
+	empty := new(span.Context).Tree()
+	if empty != nil || empty.Waterfall(40) != "" || empty.Prune(1) != nil || empty.Depth() != 0 {
+		t.Errorf("Expected empty tree to render nothing, got %+v", empty)
+	}
+	builder.Reset()
+	if err := empty.Flamegraph(builder); err != nil || builder.String() != "" {
+		t.Errorf("Expected empty flamegraph, got %q (%v)", builder.String(), err)
+	}
+}
+
+func TestAttributeTypes(t *testing.T) {
+	type payload struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	count := uint(7)
+
+	cases := []struct {
+		value    any
+		expected attribute.Type
+	}{
+		{"text", attribute.STRING},
+		{true, attribute.BOOL},
+		{42, attribute.INT64},
+		{int32(42), attribute.INT64},
+		{&count, attribute.INT64},
+		{3.14, attribute.FLOAT64},
+		{time.Second, attribute.FLOAT64},
+		{[]string{"a", "b"}, attribute.STRINGSLICE},
+		{[]int32{1, 2}, attribute.INT64SLICE},
+		{[]float32{1.5}, attribute.FLOAT64SLICE},
+		{payload{Id: 1, Name: "polygon"}, attribute.STRING},
+	}
+	for _, c := range cases {
+		if kv := span.Attribute("key", c.value); kv.Value.Type() != c.expected {
+			t.Errorf("Expected %T to map to %s, got %s", c.value, c.expected, kv.Value.Type())
+		}
+	}
+
+	if kv := span.Attribute("key", payload{Id: 1, Name: "polygon"}); kv.Value.AsString() != `{"id":1,"name":"polygon"}` {
+		t.Errorf("Expected struct to be json encoded, got %s", kv.Value.AsString())
+	}
+	if kv := span.Attribute("key", strings.Repeat("x", span.AttributeLimit*2)); len(kv.Value.AsString()) != span.AttributeLimit*2 {
+		t.Errorf("Expected plain strings to be left untouched")
+	}
+	if kv := span.Attribute("key", map[string]string{"x": strings.Repeat("x", span.AttributeLimit)}); len(kv.Value.AsString()) > span.AttributeLimit+3 {
+		t.Errorf("Expected json payload to be truncated, got %d bytes", len(kv.Value.AsString()))
+	}
+	if kv := span.Attribute("key", map[string]string{"x": strings.Repeat("€", span.AttributeLimit)}); !utf8.ValidString(kv.Value.AsString()) || len(kv.Value.AsString()) > span.AttributeLimit+3 {
+		t.Errorf("Expected truncation on a rune boundary, got %d bytes", len(kv.Value.AsString()))
+	}
+}
+
+func TestVariableRedact(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	root.Variable("userPassword", "hunter2")
+	root.Variable("userId", 1)
+
+	type login struct {
+		Username string         `json:"username"`
+		Password string         `json:"password"`
+		Extra    map[string]any `json:"extra"`
+	}
+	root.Variable("request", &login{
+		Username: "alice",
+		Password: "hunter2",
+		Extra:    map[string]any{"items": []any{map[string]any{"accessToken": "abc"}}},
+	})
+	type profile struct {
+		Username string `json:"username"`
+	}
+	root.Variable("plain", &profile{Username: "bob"})
+
+	rootSpan := root.(*span.Wrapper).Span
+	encoded := span.Attribute("request", rootSpan.Variables["request"]).Value.AsString()
+	if strings.Contains(encoded, "hunter2") || strings.Contains(encoded, "abc") || !strings.Contains(encoded, "alice") {
+		t.Errorf("Expected nested secrets to be redacted, got %s", encoded)
+	}
+	if _, ok := rootSpan.Variables["plain"].(*profile); !ok {
+		t.Errorf("Expected value without secrets to be kept as is, got %T", rootSpan.Variables["plain"])
+	}
+	if rootSpan.Variables["userPassword"] != polygon.Redacted {
+		t.Errorf("Expected password to be redacted, got %v", rootSpan.Variables["userPassword"])
+	}
+	if rootSpan.Variables["userId"] != 1 {
+		t.Errorf("Expected user id to be kept, got %v", rootSpan.Variables["userId"])
+	}
+}
+
+func TestSpanBaggage(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	if err := root.SetBaggage("tenant", "acme corp"); err != nil {
+		t.Fatalf("Failed to set baggage: %v", err)
+	}
+	if err := root.SetBaggage("", "empty"); err == nil {
+		t.Errorf("Expected invalid baggage key to fail")
+	}
+
+	// Baggage flows into forks and the outgoing context
+	child := root.Fork("service")
+	defer child.End()
+	if value := child.Baggage("tenant"); value != "acme corp" {
+		t.Errorf("Expected forked baggage, got %q", value)
+	}
+	if value := baggage.FromContext(child.Context()).Member("tenant").Value(); value != "acme corp" {
+		t.Errorf("Expected context baggage, got %q", value)
+	}
+
+	// Baggage set on a child does not leak back to its parent
+	_ = child.SetBaggage("stage", "child")
+	if value := root.Baggage("stage"); value != "" {
+		t.Errorf("Expected parent baggage to be untouched, got %q", value)
+	}
+
+	// Replacing the context keeps earlier baggage and lets new members win
+	type key struct{}
+	member, _ := baggage.NewMemberRaw("stage", "request")
+	bag, _ := baggage.New(member)
+	ctx := baggage.ContextWithBaggage(context.WithValue(context.Background(), key{}, "value"), bag)
+	child.SetContext(ctx)
+	if value := child.Baggage("tenant"); value != "acme corp" {
+		t.Errorf("Expected baggage to survive SetContext, got %q", value)
+	}
+	if value := child.Baggage("stage"); value != "request" {
+		t.Errorf("Expected new context baggage to take precedence, got %q", value)
+	}
+	if value := child.(*span.Wrapper).Span.Context.Value(key{}); value != "value" {
+		t.Errorf("Expected span context to follow SetContext, got %v", value)
+	}
+	if trace.SpanFromContext(child.Context()) != child.(*span.Wrapper).Span.TracingSpan {
+		t.Errorf("Expected current context to carry the span")
+	}
+}
+
+func TestSpanEventLink(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	// Link a batch job to the request that enqueued it
+	request := recorder.Span(context.Background(), "request", "test", nil)
+	request.End()
+	job := recorder.Span(context.Background(), "job", "test", nil)
+	job.Link(request.Context(), attribute.String("link.type", "enqueue"))
+	job.Event("job.started", attribute.Int("job.size", 3))
+	job.End()
+
+	ended := recorder.Ended("job")
+	if ended == nil {
+		t.Fatal("Expected ended job span")
+	}
+	links := ended.Links()
+	if len(links) != 1 || links[0].SpanContext.SpanID() != trace.SpanContextFromContext(request.Context()).SpanID() {
+		t.Fatalf("Expected link to request span, got %+v", links)
+	}
+	linkAttributes := attribute.NewSet(links[0].Attributes...)
+	if value, _ := linkAttributes.Value("link.type"); value.AsString() != "enqueue" {
+		t.Errorf("Expected link attribute, got %q", value.AsString())
+	}
+	events := ended.Events()
+	if len(events) != 1 || events[0].Name != "job.started" {
+		t.Fatalf("Expected job.started event, got %+v", events)
+	}
+	eventAttributes := attribute.NewSet(events[0].Attributes...)
+	if value, _ := eventAttributes.Value("job.size"); value.AsInt64() != 3 {
+		t.Errorf("Expected event attribute, got %d", value.AsInt64())
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	cases := []struct {
+		err      error
+		expected int
+	}{
+		{root.Error("plain failure", errors.New("boom")), 400},
+		{root.Error("user not found", fmt.Errorf("lookup: %w", span.ErrNotFound)), 404},
+		{root.ErrorDimension(polygon.DimensionTypeTimeout, polygon.DimensionScopeDatabase, "query timed out", nil), 504},
+		{root.ErrorDimension(polygon.DimensionTypeOverflow, polygon.DimensionScopeExternal, "quota exceeded", nil), 429},
+		{root.Error("outer", root.ErrorDimension(polygon.DimensionTypeFatal, polygon.DimensionScopeDatabase, "inner", nil)), 500},
+		{root.ErrorStatus(409, "outer", root.ErrorDimension(polygon.DimensionTypeFatal, polygon.DimensionScopeDatabase, "inner", nil)), 409},
+	}
+	for _, c := range cases {
+		var spanError *span.Error
+		if !errors.As(c.err, &spanError) {
+			t.Fatalf("Expected span error, got %T", c.err)
+		}
+		if status := spanError.Status(); status != c.expected {
+			t.Errorf("Expected status %d for %q, got %d", c.expected, c.err, status)
+		}
+	}
+
+	// * several wrapped sentinels resolve in declaration order
+	wrapped := errors.Join(span.ErrForbidden, span.ErrConflict, span.ErrNotFound)
+	for range 50 {
+		if status, ok := span.ErrorSentinelStatus(wrapped); !ok || status != 404 {
+			t.Fatalf("Expected first declared sentinel status 404, got %d", status)
+		}
+	}
+	wrapped = fmt.Errorf("%w: %w", span.ErrForbidden, span.ErrUnauthorized)
+	if status, _ := span.ErrorSentinelStatus(wrapped); status != 401 {
+		t.Errorf("Expected sentinel status 401, got %d", status)
+	}
+}
+
+func TestForkHierarchy(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	service := root.Fork("service")
+	defer service.End()
+	database := service.Fork("database")
+	defer database.End()
+
+	// Each fork is a child of the span it was forked from
+	parentOf := func(s polygon.Span) trace.SpanID {
+		return s.(*span.Wrapper).Span.TracingSpan.(sdktrace.ReadOnlySpan).Parent().SpanID()
+	}
+	spanOf := func(s polygon.Span) trace.SpanID {
+		return trace.SpanContextFromContext(s.Context()).SpanID()
+	}
+	if parentOf(service) != spanOf(root) {
+		t.Errorf("Expected service to be a child of root")
+	}
+	if parentOf(database) != spanOf(service) {
+		t.Errorf("Expected database to be a child of service")
+	}
+
+	// Replacing the context keeps the active span
+	type key struct{}
+	database.SetContext(context.WithValue(context.Background(), key{}, "value"))
+	if database.Context().Value(key{}) != "value" || spanOf(database) != database.(*span.Wrapper).Span.TracingSpan.SpanContext().SpanID() {
+		t.Errorf("Expected context to carry both value and active span")
+	}
+}
+
+func TestErrorRecord(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	ended := func(s polygon.Span) sdktrace.ReadOnlySpan {
+		id := s.(*span.Wrapper).Span.TracingSpan.SpanContext().SpanID()
+		for _, e := range recorder.Spans.Ended() {
+			if e.SpanContext().SpanID() == id {
+				return e
+			}
+		}
+		t.Fatalf("Expected span %s to be ended", id)
+		return nil
+	}
+
+	// Fail the innermost span and wrap the error at the root while the chain is still open
+	root := recorder.Span(context.Background(), "root", "handler", nil)
+	service := root.Fork("service")
+	repository := service.Fork("repository")
+	inner := repository.ErrorDimension(polygon.DimensionTypeTimeout, polygon.DimensionScopeDatabase, "query timed out", errors.New("context deadline exceeded"))
+	_ = root.Error("load failed", inner)
+	repository.End()
+	service.End()
+	root.End()
+
+	cases := []struct {
+		span        polygon.Span
+		description string
+		exception   bool
+	}{
+		{repository, "query timed out", true},
+		{service, "load failed", false},
+		{root, "load failed", true},
+	}
+	for _, c := range cases {
+		e := ended(c.span)
+		if e.Status().Code != codes.Error || e.Status().Description != c.description {
+			t.Errorf("Expected error status %q on %s, got %v", c.description, e.Name(), e.Status())
+		}
+		exceptions := 0
+		for _, event := range e.Events() {
+			if event.Name != semconv.ExceptionEventName {
+				continue
+			}
+			exceptions++
+			caller := ""
+			for _, kv := range event.Attributes {
+				if kv.Key == "span.caller" {
+					caller = kv.Value.AsString()
+				}
+			}
+			if !strings.HasPrefix(caller, "span_test.TestErrorRecord:") {
+				t.Errorf("Expected exception caller in test on %s, got %q", e.Name(), caller)
+			}
+		}
+		if (exceptions == 1) != c.exception {
+			t.Errorf("Expected exception event %v on %s, got %d", c.exception, e.Name(), exceptions)
+		}
+	}
+
+	// Classification lands on the failing span and the error counter
+	attributes := attribute.NewSet(ended(repository).Attributes()...)
+	if value, _ := attributes.Value(semconv.ErrorTypeKey); value.AsString() != "timeout" {
+		t.Errorf("Expected error.type timeout, got %q", value.AsString())
+	}
+	if value, _ := attributes.Value("error.scope"); value.AsString() != "database" {
+		t.Errorf("Expected error.scope database, got %q", value.AsString())
+	}
+	rootAttributes := attribute.NewSet(ended(root).Attributes()...)
+	if _, ok := rootAttributes.Value(semconv.ErrorTypeKey); ok {
+		t.Error("Expected no error.type on unclassified root span")
+	}
+
+	points := recorder.Sum("app.error.count")
+	if len(points) != 1 || points[0].Value != 1 {
+		t.Fatalf("Expected one error count point, got %v", points)
+	}
+	for key, expected := range map[attribute.Key]string{
+		semconv.ErrorTypeKey: "timeout",
+		"error.scope":        "database",
+		"span.layer":         "repository",
+	} {
+		if value, _ := points[0].Attributes.Value(key); value.AsString() != expected {
+			t.Errorf("Expected counter label %s = %s, got %q", key, expected, value.AsString())
+		}
+	}
+}
+
+func TestErrorChain(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{root.Error("bare failure", nil), "bare failure"},
+		{root.Error("flush failed", context.DeadlineExceeded), "flush failed: context deadline exceeded"},
+		{root.Error("shutdown failed", root.Fork("exporter").Error("flush failed", context.DeadlineExceeded)), "shutdown failed: flush failed: context deadline exceeded"},
+	}
+	for _, c := range cases {
+		if message := c.err.Error(); message != c.expected {
+			t.Errorf("Expected %q, got %q", c.expected, message)
+		}
+	}
+
+	if !errors.Is(cases[2].err, context.DeadlineExceeded) {
+		t.Error("Expected wrapped span error to unwrap to its root cause")
+	}
+	if errors.Unwrap(cases[0].err) != nil {
+		t.Error("Expected bare span error to unwrap to nil")
+	}
+}
+
+func TestErrorCaller(t *testing.T) {
+	p := newPolygon(t)
+	root := p.Span(context.Background(), "root", "test", nil)
+	defer root.End()
+	inner := root.(*span.Wrapper).Span
+
+	// Every error entry point records the frame that called it
+	cases := []error{
+		root.Error("wrapper error", nil),
+		root.ErrorDimension(polygon.DimensionTypeFatal, polygon.DimensionScopeDatabase, "wrapper dimension", nil),
+		root.ErrorStatus(409, "wrapper status", nil),
+		inner.Error("span error", nil),
+		inner.ErrorDimension(polygon.DimensionTypeFatal, polygon.DimensionScopeDatabase, "span dimension", nil),
+		inner.ErrorStatus(409, "span status", nil),
+	}
+	for _, err := range cases {
+		var spanError *span.Error
+		if !errors.As(err, &spanError) {
+			t.Fatalf("Expected span error, got %T", err)
+		}
+		if caller := spanError.Items[0].Trace.String(); !strings.HasPrefix(caller, "span_test.TestErrorCaller:") {
+			t.Errorf("Expected caller in test for %q, got %s", err, caller)
+		}
+	}
+}
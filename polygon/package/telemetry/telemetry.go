@@ -2,14 +2,17 @@ package telemetry
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"os"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -20,10 +23,16 @@ import (
 )
 
 type Telemetry struct {
-	Polygon    polygon.Polygon
-	Meter      metric.Meter
-	Tracer     trace.Tracer
-	Instrument *Instrument
+	Polygon        polygon.Polygon
+	Meter          metric.Meter
+	MeterProvider  *sdkmetric.MeterProvider
+	Tracer         trace.Tracer
+	TracerProvider *sdktrace.TracerProvider
+	Instrument     *Instrument
+	Logger         *slog.Logger
+	LoggerProvider *sdklog.LoggerProvider
+	Propagator     propagation.TextMapPropagator
+	File           *os.File
 }
 
 func New(polygon polygon.Polygon) (_ *Telemetry, err error) {
@@ -34,6 +43,16 @@ func New(polygon polygon.Polygon) (_ *Telemetry, err error) {
 		Tracer:  nil,
 	}
 
+	// * release partially constructed telemetry on failure
+	defer func() {
+		if err == nil {
+			return
+		}
+		if shutdownErr := telemetry.Shutdown(context.Background()); shutdownErr != nil {
+			err = errors.Join(err, shutdownErr)
+		}
+	}()
+
 	// * construct resource
 	attributes := make([]attribute.KeyValue, 0)
 	if telemetry.Polygon.Config().AppName != nil {
@@ -59,72 +78,88 @@ func New(polygon polygon.Polygon) (_ *Telemetry, err error) {
 		return nil, err
 	}
 
+	// * construct runtime collector
+	if telemetry.Polygon.Config().TelemetryRuntime != nil && *telemetry.Polygon.Config().TelemetryRuntime {
+		if err := NewRuntime(telemetry); err != nil {
+			return nil, err
+		}
+	}
+
 	// * construct tracer
 	telemetry.Tracer, err = NewTracer(telemetry, res)
 	if err != nil {
 		return nil, err
 	}
 
+	// * construct logger
+	telemetry.Logger, err = NewLogger(telemetry, res)
+	if err != nil {
+		return nil, err
+	}
+
 	// * construct instrument
 	telemetry.Instrument, err = NewInstrument(telemetry.Meter)
+	if err != nil {
+		return nil, span.NewError(nil, "unable to initialize instrument", err)
+	}
+
+	// * register global providers
+	otel.SetMeterProvider(telemetry.MeterProvider)
+	otel.SetTracerProvider(telemetry.TracerProvider)
+	otel.SetTextMapPropagator(telemetry.Propagator)
+	if telemetry.LoggerProvider != nil {
+		global.SetLoggerProvider(telemetry.LoggerProvider)
+	}
 
 	return telemetry, nil
 }
 
 func NewMeter(telemetry *Telemetry, res *resource.Resource) (metric.Meter, error) {
 	// * construct exporter
-	exporter, err := otlpmetricgrpc.New(
-		context.Background(),
-		otlpmetricgrpc.WithEndpoint(*telemetry.Polygon.Config().TelemetryUrl),
-		otlpmetricgrpc.WithHeaders(map[string]string{
-			"X-Scope-OrgID": *telemetry.Polygon.Config().TelemetryOrganization,
-		}),
-		otlpmetricgrpc.WithInsecure(),
-	)
+	exporter, err := NewMetricExporter(telemetry)
 	if err != nil {
 		return nil, span.NewError(nil, "unable to initialize metric exporter", err)
 	}
 
 	// * construct provider
-	provider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
+	options := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+	}
+	if exporter != nil {
+		options = append(options, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
 			exporter,
 			sdkmetric.WithInterval(time.Minute),
-		)),
-		sdkmetric.WithResource(res),
-	)
-
-	otel.SetMeterProvider(provider)
-
-	mm := otel.Meter("polygon-meter")
+		)))
+	}
+	provider := sdkmetric.NewMeterProvider(options...)
+	telemetry.MeterProvider = provider
 
-	return mm, nil
+	return provider.Meter("polygon-meter"), nil
 }
 
 func NewTracer(telemetry *Telemetry, res *resource.Resource) (trace.Tracer, error) {
 	// * construct exporter
-	exporter, err := otlptracegrpc.New(
-		context.Background(),
-		otlptracegrpc.WithEndpoint(*telemetry.Polygon.Config().TelemetryUrl),
-		otlptracegrpc.WithHeaders(map[string]string{
-			"X-Scope-OrgID": *telemetry.Polygon.Config().TelemetryOrganization,
-		}),
-		otlptracegrpc.WithInsecure(),
-	)
+	exporter, err := NewTraceExporter(telemetry)
 	if err != nil {
 		return nil, span.NewError(nil, "unable to intialize exporter", err)
 	}
 
 	// * construct provider
-	provider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+	options := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
-	)
-
-	otel.SetTracerProvider(provider)
-	otel.SetTextMapPropagator(propagation.TraceContext{})
+		sdktrace.WithSampler(NewSampler(telemetry.Polygon.Config().TelemetrySampling)),
+	}
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(options...)
+	telemetry.TracerProvider = provider
 
-	tt := otel.Tracer("polygon-tracer")
+	// * construct propagator
+	telemetry.Propagator, err = NewPropagator(telemetry)
+	if err != nil {
+		return nil, err
+	}
 
-	return tt, nil
+	return provider.Tracer("polygon-tracer"), nil
 }
@@ -0,0 +1,220 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+func (r *Telemetry) Exporter() polygon.TelemetryExporter {
+	config := r.Polygon.Config()
+	if config.TelemetryExporter != nil {
+		return *config.TelemetryExporter
+	}
+
+	// * fallback to grpc when url is configured for backward compatibility
+	if config.TelemetryUrl != nil {
+		return polygon.TelemetryExporterGrpc
+	}
+
+	return polygon.TelemetryExporterNone
+}
+
+func (r *Telemetry) ExporterUrl() (string, error) {
+	if r.Polygon.Config().TelemetryUrl == nil {
+		return "", span.NewError(nil, fmt.Sprintf("telemetry url is required for %s exporter", r.Exporter()), nil)
+	}
+	return *r.Polygon.Config().TelemetryUrl, nil
+}
+
+func (r *Telemetry) ExporterHeaders() map[string]string {
+	headers := make(map[string]string)
+	if r.Polygon.Config().TelemetryOrganization != nil {
+		headers["X-Scope-OrgID"] = *r.Polygon.Config().TelemetryOrganization
+	}
+	return headers
+}
+
+func (r *Telemetry) ExporterWriter() (io.Writer, error) {
+	switch r.Exporter() {
+	case polygon.TelemetryExporterStdout:
+		return os.Stdout, nil
+	case polygon.TelemetryExporterFile:
+		if r.File != nil {
+			return r.File, nil
+		}
+		if r.Polygon.Config().TelemetryFile == nil {
+			return nil, span.NewError(nil, "telemetry file is required for file exporter", nil)
+		}
+
+		// * open shared jsonl file
+		file, err := os.OpenFile(*r.Polygon.Config().TelemetryFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, span.NewError(nil, "unable to open telemetry file", err)
+		}
+		r.File = file
+		return r.File, nil
+	default:
+		return nil, span.NewError(nil, fmt.Sprintf("no writer for %s exporter", r.Exporter()), nil)
+	}
+}
+
+func NewMetricExporter(telemetry *Telemetry) (sdkmetric.Exporter, error) {
+	switch telemetry.Exporter() {
+	case polygon.TelemetryExporterGrpc:
+		url, err := telemetry.ExporterUrl()
+		if err != nil {
+			return nil, err
+		}
+		return otlpmetricgrpc.New(
+			context.Background(),
+			otlpmetricgrpc.WithEndpoint(url),
+			otlpmetricgrpc.WithHeaders(telemetry.ExporterHeaders()),
+			otlpmetricgrpc.WithInsecure(),
+		)
+	case polygon.TelemetryExporterHttp:
+		url, err := telemetry.ExporterUrl()
+		if err != nil {
+			return nil, err
+		}
+		return otlpmetrichttp.New(
+			context.Background(),
+			otlpmetrichttp.WithEndpoint(url),
+			otlpmetrichttp.WithHeaders(telemetry.ExporterHeaders()),
+			otlpmetrichttp.WithInsecure(),
+		)
+	case polygon.TelemetryExporterStdout:
+		writer, err := telemetry.ExporterWriter()
+		if err != nil {
+			return nil, err
+		}
+		return stdoutmetric.New(
+			stdoutmetric.WithWriter(writer),
+			stdoutmetric.WithPrettyPrint(),
+		)
+	case polygon.TelemetryExporterFile:
+		writer, err := telemetry.ExporterWriter()
+		if err != nil {
+			return nil, err
+		}
+		return stdoutmetric.New(
+			stdoutmetric.WithWriter(writer),
+		)
+	case polygon.TelemetryExporterNone:
+		return nil, nil
+	default:
+		return nil, span.NewError(nil, fmt.Sprintf("unknown telemetry exporter %s", telemetry.Exporter()), nil)
+	}
+}
+
+func NewTraceExporter(telemetry *Telemetry) (sdktrace.SpanExporter, error) {
+	switch telemetry.Exporter() {
+	case polygon.TelemetryExporterGrpc:
+		url, err := telemetry.ExporterUrl()
+		if err != nil {
+			return nil, err
+		}
+		return otlptracegrpc.New(
+			context.Background(),
+			otlptracegrpc.WithEndpoint(url),
+			otlptracegrpc.WithHeaders(telemetry.ExporterHeaders()),
+			otlptracegrpc.WithInsecure(),
+		)
+	case polygon.TelemetryExporterHttp:
+		url, err := telemetry.ExporterUrl()
+		if err != nil {
+			return nil, err
+		}
+		return otlptracehttp.New(
+			context.Background(),
+			otlptracehttp.WithEndpoint(url),
+			otlptracehttp.WithHeaders(telemetry.ExporterHeaders()),
+			otlptracehttp.WithInsecure(),
+		)
+	case polygon.TelemetryExporterStdout:
+		writer, err := telemetry.ExporterWriter()
+		if err != nil {
+			return nil, err
+		}
+		return stdouttrace.New(
+			stdouttrace.WithWriter(writer),
+			stdouttrace.WithPrettyPrint(),
+		)
+	case polygon.TelemetryExporterFile:
+		writer, err := telemetry.ExporterWriter()
+		if err != nil {
+			return nil, err
+		}
+		return stdouttrace.New(
+			stdouttrace.WithWriter(writer),
+		)
+	case polygon.TelemetryExporterNone:
+		return nil, nil
+	default:
+		return nil, span.NewError(nil, fmt.Sprintf("unknown telemetry exporter %s", telemetry.Exporter()), nil)
+	}
+}
+
+func NewLogExporter(telemetry *Telemetry) (sdklog.Exporter, error) {
+	switch telemetry.Exporter() {
+	case polygon.TelemetryExporterGrpc:
+		url, err := telemetry.ExporterUrl()
+		if err != nil {
+			return nil, err
+		}
+		return otlploggrpc.New(
+			context.Background(),
+			otlploggrpc.WithEndpoint(url),
+			otlploggrpc.WithHeaders(telemetry.ExporterHeaders()),
+			otlploggrpc.WithInsecure(),
+		)
+	case polygon.TelemetryExporterHttp:
+		url, err := telemetry.ExporterUrl()
+		if err != nil {
+			return nil, err
+		}
+		return otlploghttp.New(
+			context.Background(),
+			otlploghttp.WithEndpoint(url),
+			otlploghttp.WithHeaders(telemetry.ExporterHeaders()),
+			otlploghttp.WithInsecure(),
+		)
+	case polygon.TelemetryExporterStdout:
+		writer, err := telemetry.ExporterWriter()
+		if err != nil {
+			return nil, err
+		}
+		return stdoutlog.New(
+			stdoutlog.WithWriter(writer),
+			stdoutlog.WithPrettyPrint(),
+		)
+	case polygon.TelemetryExporterFile:
+		writer, err := telemetry.ExporterWriter()
+		if err != nil {
+			return nil, err
+		}
+		return stdoutlog.New(
+			stdoutlog.WithWriter(writer),
+		)
+	case polygon.TelemetryExporterNone:
+		return nil, nil
+	default:
+		return nil, span.NewError(nil, fmt.Sprintf("unknown telemetry exporter %s", telemetry.Exporter()), nil)
+	}
+}
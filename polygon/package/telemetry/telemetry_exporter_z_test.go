@@ -0,0 +1,91 @@
+package telemetry_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsthun/gut"
+	"go.opentelemetry.io/otel"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/core"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	cases := []struct {
+		name     string
+		config   *polygon.Config
+		expected polygon.TelemetryExporter
+		exporter bool
+		err      bool
+	}{
+		{"default without url", &polygon.Config{}, polygon.TelemetryExporterNone, false, false},
+		{"default with url", &polygon.Config{TelemetryUrl: gut.Ptr("localhost:4317")}, polygon.TelemetryExporterGrpc, true, false},
+		{"explicit none", &polygon.Config{TelemetryUrl: gut.Ptr("localhost:4317"), TelemetryExporter: gut.Ptr(polygon.TelemetryExporterNone)}, polygon.TelemetryExporterNone, false, false},
+		{"stdout", &polygon.Config{TelemetryExporter: gut.Ptr(polygon.TelemetryExporterStdout)}, polygon.TelemetryExporterStdout, true, false},
+		{"file", &polygon.Config{TelemetryExporter: gut.Ptr(polygon.TelemetryExporterFile), TelemetryFile: &file}, polygon.TelemetryExporterFile, true, false},
+		{"file without path", &polygon.Config{TelemetryExporter: gut.Ptr(polygon.TelemetryExporterFile)}, polygon.TelemetryExporterFile, false, true},
+		{"http without url", &polygon.Config{TelemetryExporter: gut.Ptr(polygon.TelemetryExporterHttp)}, polygon.TelemetryExporterHttp, false, true},
+		{"unknown", &polygon.Config{TelemetryExporter: gut.Ptr(polygon.TelemetryExporter("kafka"))}, "kafka", false, true},
+	}
+	for _, c := range cases {
+		recorder, err := telemetrytest.New(c.config)
+		if err != nil {
+			t.Fatalf("Failed to create recorder: %v", err)
+		}
+		instance := &telemetry.Telemetry{Polygon: recorder}
+		if exporter := instance.Exporter(); exporter != c.expected {
+			t.Errorf("%s: expected %s exporter, got %s", c.name, c.expected, exporter)
+		}
+
+		exporter, err := telemetry.NewTraceExporter(instance)
+		if (err != nil) != c.err {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.err, err)
+		}
+		if (exporter != nil) != c.exporter {
+			t.Errorf("%s: expected exporter %v, got %T", c.name, c.exporter, exporter)
+		}
+		if err := instance.Shutdown(t.Context()); err != nil {
+			t.Errorf("%s: failed to shutdown: %v", c.name, err)
+		}
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("Expected file exporter to create %s: %v", file, err)
+	}
+}
+
+func TestNewReleasesOnFailure(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	tracerProvider := otel.GetTracerProvider()
+	meterProvider := otel.GetMeterProvider()
+
+	// Fail the logger after the meter, runtime collector and tracer are built
+	_, err := core.New(&polygon.Config{
+		TelemetryExporter: gut.Ptr(polygon.TelemetryExporterFile),
+		TelemetryFile:     &file,
+		TelemetryRuntime:  gut.Ptr(true),
+		LogFormat:         gut.Ptr(polygon.LogFormat("xml")),
+	})
+	if err == nil {
+		t.Fatal("Expected unknown log format to fail")
+	}
+
+	if otel.GetTracerProvider() != tracerProvider || otel.GetMeterProvider() != meterProvider {
+		t.Error("Expected global providers to be untouched after failure")
+	}
+
+	// The shared exporter file is closed again
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("procfs not available")
+	}
+	for _, entry := range entries {
+		if target, _ := os.Readlink(filepath.Join("/proc/self/fd", entry.Name())); target == file {
+			t.Errorf("Expected telemetry file to be closed, still open as fd %s", entry.Name())
+		}
+	}
+}
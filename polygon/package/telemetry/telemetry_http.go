@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.scnd.dev/open/polygon"
+)
+
+func (r *Telemetry) HttpConfig() *polygon.TelemetryHttp {
+	if r.Polygon.Config().TelemetryHttp == nil {
+		return new(polygon.TelemetryHttp)
+	}
+	return r.Polygon.Config().TelemetryHttp
+}
+
+func (r *Telemetry) HttpRedacted(key string) bool {
+	return r.Polygon.Config().Redacted(key)
+}
+
+func (r *Telemetry) HttpRequestAttributes(c fiber.Ctx) []attribute.KeyValue {
+	config := r.HttpConfig()
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(strings.Clone(c.Method())),
+		semconv.URLScheme(strings.Clone(c.Scheme())),
+		semconv.URLPath(strings.Clone(c.Path())),
+		semconv.UserAgentOriginal(strings.Clone(c.Get(fiber.HeaderUserAgent))),
+	}
+
+	// * construct allowed query
+	if query := r.HttpQuery(c); query != "" {
+		attributes = append(attributes, semconv.URLQuery(query))
+	}
+
+	// * capture request headers
+	for _, header := range config.RequestHeaders {
+		values := HttpHeaderValues(c.Request().Header.PeekAll(header))
+		if len(values) == 0 {
+			continue
+		}
+		attributes = append(attributes, semconv.HTTPRequestHeader(strings.ToLower(header), r.HttpRedact(header, values)...))
+	}
+
+	return attributes
+}
+
+func (r *Telemetry) HttpResponseAttributes(c fiber.Ctx, route string) []attribute.KeyValue {
+	config := r.HttpConfig()
+	attributes := []attribute.KeyValue{
+		semconv.HTTPResponseStatusCode(c.Response().StatusCode()),
+	}
+	if route != "" {
+		attributes = append(attributes, semconv.HTTPRoute(route))
+	}
+
+	// * capture response headers
+	for _, header := range config.ResponseHeaders {
+		values := HttpHeaderValues(c.Response().Header.PeekAll(header))
+		if len(values) == 0 {
+			continue
+		}
+		attributes = append(attributes, semconv.HTTPResponseHeader(strings.ToLower(header), r.HttpRedact(header, values)...))
+	}
+
+	return attributes
+}
+
+func (r *Telemetry) HttpQuery(c fiber.Ctx) string {
+	queries := c.Queries()
+	values := make(url.Values)
+	for _, key := range r.HttpConfig().QueryParameters {
+		value, ok := queries[key]
+		if !ok {
+			continue
+		}
+		if r.HttpRedacted(key) {
+			value = polygon.Redacted
+		}
+		values.Set(key, strings.Clone(value))
+	}
+	return values.Encode()
+}
+
+func (r *Telemetry) HttpRedact(key string, values []string) []string {
+	redacted := slices.Clone(values)
+	for i := range redacted {
+		if r.HttpRedacted(key) {
+			redacted[i] = polygon.Redacted
+		} else {
+			redacted[i] = strings.Clone(redacted[i])
+		}
+	}
+	return redacted
+}
+
+func HttpHeaderValues(values [][]byte) []string {
+	headers := make([]string, 0, len(values))
+	for _, value := range values {
+		headers = append(headers, string(value))
+	}
+	return headers
+}
+
+func HttpRoute(c fiber.Ctx) string {
+	if !c.Matched() {
+		return ""
+	}
+	return c.Route().Path
+}
@@ -5,11 +5,16 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.scnd.dev/open/polygon"
 )
 
 type Instrument struct {
 	HttpDurationHistogram          metric.Int64Histogram
 	HttpActiveRequestUpDownCounter metric.Int64UpDownCounter
+	HttpClientDurationHistogram    metric.Int64Histogram
+	ErrorCounter                   metric.Int64Counter
+	Registry                       *Registry
 }
 
 func NewInstrument(meter metric.Meter) (*Instrument, error) {
@@ -30,29 +35,90 @@ func NewInstrument(meter metric.Meter) (*Instrument, error) {
 		return nil, err
 	}
 
+	httpClientDurationHistogram, err := meter.Int64Histogram(
+		"app.http.client.duration",
+		metric.WithDescription("Duration of outgoing HTTP requests"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	errorCounter, err := meter.Int64Counter(
+		"app.error.count",
+		metric.WithDescription("Number of classified span errors"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Instrument{
 		HttpDurationHistogram:          httpDurationHistogram,
 		HttpActiveRequestUpDownCounter: httpActiveRequestUpDownCounter,
+		HttpClientDurationHistogram:    httpClientDurationHistogram,
+		ErrorCounter:                   errorCounter,
+		Registry:                       NewRegistry(meter),
 	}, nil
 }
 
-func (r *Instrument) HttpDurationRecord(ctx context.Context, duration int64, path string, status int) {
+func (r *Instrument) HttpDurationRecord(ctx context.Context, duration int64, method string, route string, status int) {
 	r.HttpDurationHistogram.Record(
 		ctx,
 		duration,
 		metric.WithAttributes(
-			attribute.String("http.path", path),
-			attribute.Int("http.status", status),
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
 		),
 	)
 }
 
-func (r *Instrument) HttpActiveRequestCounter(ctx context.Context, delta int64, path string) {
+func (r *Instrument) HttpActiveRequestCounter(ctx context.Context, delta int64, method string) {
 	r.HttpActiveRequestUpDownCounter.Add(
 		ctx,
 		delta,
 		metric.WithAttributes(
-			attribute.String("http.path", path),
+			semconv.HTTPRequestMethodKey.String(method),
+		),
+	)
+}
+
+func (r *Instrument) HttpClientDurationRecord(ctx context.Context, duration int64, method string, host string, status int) {
+	r.HttpClientDurationHistogram.Record(
+		ctx,
+		duration,
+		metric.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.ServerAddress(host),
+			semconv.HTTPResponseStatusCode(status),
+		),
+	)
+}
+
+func (r *Instrument) ErrorCountRecord(ctx context.Context, dimensionType polygon.DimensionType, dimensionScope polygon.DimensionScope, layer string) {
+	r.ErrorCounter.Add(
+		ctx,
+		1,
+		metric.WithAttributes(
+			semconv.ErrorTypeKey.String(string(dimensionType)),
+			attribute.String("error.scope", string(dimensionScope)),
+			attribute.String("span.layer", layer),
 		),
 	)
 }
+
+func (r *Instrument) Counter(definition *polygon.InstrumentDefinition) (polygon.Counter, error) {
+	return r.Registry.Counter(definition)
+}
+
+func (r *Instrument) UpDownCounter(definition *polygon.InstrumentDefinition) (polygon.UpDownCounter, error) {
+	return r.Registry.UpDownCounter(definition)
+}
+
+func (r *Instrument) Histogram(definition *polygon.InstrumentDefinition) (polygon.Histogram, error) {
+	return r.Registry.Histogram(definition)
+}
+
+func (r *Instrument) Gauge(definition *polygon.InstrumentDefinition, callback polygon.GaugeCallback) error {
+	return r.Registry.Gauge(definition, callback)
+}
@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+type LevelHandler struct {
+	Level   slog.Leveler
+	Handler slog.Handler
+}
+
+func (r *LevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= r.Level.Level() && r.Handler.Enabled(ctx, level)
+}
+
+func (r *LevelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return r.Handler.Handle(ctx, record)
+}
+
+func (r *LevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LevelHandler{
+		Level:   r.Level,
+		Handler: r.Handler.WithAttrs(attrs),
+	}
+}
+
+func (r *LevelHandler) WithGroup(name string) slog.Handler {
+	return &LevelHandler{
+		Level:   r.Level,
+		Handler: r.Handler.WithGroup(name),
+	}
+}
+
+func NewLogger(telemetry *Telemetry, res *resource.Resource) (*slog.Logger, error) {
+	// * resolve level
+	level := slog.LevelInfo
+	if telemetry.Polygon.Config().LogLevel != nil {
+		level = *telemetry.Polygon.Config().LogLevel
+	}
+
+	// * resolve format
+	format := polygon.LogFormatText
+	if telemetry.Polygon.Config().LogFormat != nil {
+		format = *telemetry.Polygon.Config().LogFormat
+	}
+
+	switch format {
+	case polygon.LogFormatText:
+		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})), nil
+	case polygon.LogFormatJson:
+		return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})), nil
+	case polygon.LogFormatOtlp:
+		// * construct exporter
+		exporter, err := NewLogExporter(telemetry)
+		if err != nil {
+			return nil, span.NewError(nil, "unable to initialize log exporter", err)
+		}
+
+		// * construct provider
+		options := []sdklog.LoggerProviderOption{
+			sdklog.WithResource(res),
+		}
+		if exporter != nil {
+			options = append(options, sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+		}
+		provider := sdklog.NewLoggerProvider(options...)
+		telemetry.LoggerProvider = provider
+
+		handler := otelslog.NewHandler("polygon-logger", otelslog.WithLoggerProvider(provider))
+		return slog.New(&LevelHandler{
+			Level:   level,
+			Handler: handler,
+		}), nil
+	default:
+		return nil, span.NewError(nil, fmt.Sprintf("unknown log format %s", format), nil)
+	}
+}
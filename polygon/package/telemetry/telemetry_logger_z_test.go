@@ -0,0 +1,68 @@
+package telemetry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestSpanLog(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	buffer := new(bytes.Buffer)
+	recorder.Telemetry.Logger = slog.New(&telemetry.LevelHandler{
+		Level:   slog.LevelWarn,
+		Handler: slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	})
+
+	s := recorder.Span(context.Background(), "root", "handler", nil)
+	defer s.End()
+	s.Variable("user", 42)
+	s.Variable("password", "hunter2")
+
+	// Records below the handler level are dropped
+	s.Log(slog.LevelInfo, "filtered")
+	s.Log(slog.LevelWarn, "slow query", "duration", 3)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected one record above warn, got %q", buffer.String())
+	}
+	record := make(map[string]any)
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Failed to decode record: %v", err)
+	}
+
+	spanContext := trace.SpanContextFromContext(s.Context())
+	expected := map[string]any{
+		"msg":        "slow query",
+		"level":      "WARN",
+		"span.name":  "root",
+		"span.layer": "handler",
+		"trace_id":   spanContext.TraceID().String(),
+		"span_id":    spanContext.SpanID().String(),
+		"duration":   float64(3),
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, record[key])
+		}
+	}
+	if caller, _ := record["span.caller"].(string); !strings.HasPrefix(caller, "telemetry_test.TestSpanLog:") {
+		t.Errorf("Expected span caller in test, got %q", caller)
+	}
+	variables, _ := record["var"].(map[string]any)
+	if variables["user"] != float64(42) || variables["password"] != polygon.Redacted {
+		t.Errorf("Expected var group with redacted password, got %v", record["var"])
+	}
+}
@@ -2,44 +2,125 @@ package telemetry
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
-	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
 	"go.scnd.dev/open/polygon/package/span"
 )
 
+const (
+	HeaderDebug     = "X-Polygon-Debug"
+	HeaderDebugTree = "X-Polygon-Span-Tree"
+)
+
+const HeaderDebugTreeLimit = 4096
+
+type localSpanKey struct{}
+
 func (r *Telemetry) Middleware() fiber.Handler {
 	return func(c fiber.Ctx) error {
-		name := fmt.Sprintf("HTTP %s %s", c.Method(), c.OriginalURL())
+		method := strings.Clone(c.Method())
+		name := fmt.Sprintf("HTTP %s", method)
+
+		// * extract upstream context
+		upstream := otel.GetTextMapPropagator().Extract(c.RequestCtx(), &HeaderCarrier{Ctx: c})
+
+		// * start server span
 		s := &span.Wrapper{
-			Span: span.NewContext(r.Polygon, c.RequestCtx(), name, "trace", make(map[string]any)),
+			Span: span.NewContext(
+				r.Polygon,
+				upstream,
+				name,
+				"trace",
+				make(map[string]any),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(r.HttpRequestAttributes(c)...),
+			),
 		}
 		defer s.End()
 
-		// * start tracer
-		context, traceSpan := r.Tracer.Start(s.Context(), name)
-
 		// * set context
-		c.SetContext(context)
-
-		// * set attributes
-		traceSpan.SetAttributes(
-			attribute.String("http.method", c.Method()),
-			attribute.String("http.url", c.OriginalURL()),
-			attribute.String("http.user_agent", c.Get("User-Agent")),
-		)
+		c.SetContext(s.Span.TracingContext)
+		c.Locals(localSpanKey{}, s)
 
 		// * count metric
-		r.Instrument.HttpActiveRequestCounter(context, 1, c.OriginalURL())
-		defer r.Instrument.HttpActiveRequestCounter(context, -1, c.OriginalURL())
+		r.Instrument.HttpActiveRequestCounter(s.Span.TracingContext, 1, method)
+		defer r.Instrument.HttpActiveRequestCounter(s.Span.TracingContext, -1, method)
+
+		// * record panic escaping downstream handlers before span ends
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			panicErr, ok := value.(*PanicError)
+			if !ok {
+				panicErr = NewPanicError(value)
+				_ = RecordPanic(s, panicErr)
+			}
+			c.Status(fiber.StatusInternalServerError)
+			r.Finish(c, s, method)
+			panic(panicErr)
+		}()
 
 		// * proceed to next
 		err := c.Next()
-
-		// * count metric
-		r.Instrument.HttpDurationRecord(context, time.Now().Sub(*s.Started()).Milliseconds(), c.OriginalURL(), c.Response().StatusCode())
-		traceSpan.SetAttributes(attribute.Int("http.status_code", c.Response().StatusCode()))
+		r.Finish(c, s, method)
 		return err
 	}
 }
+
+func (r *Telemetry) Finish(c fiber.Ctx, s *span.Wrapper, method string) {
+	// * resolve route template
+	route := HttpRoute(c)
+	if route != "" {
+		s.Span.SetName(fmt.Sprintf("HTTP %s %s", method, route))
+	}
+
+	// * attach span tree in development
+	if c.Get(HeaderDebug) != "" && r.Polygon.Config().AppEnvironment != nil && *r.Polygon.Config().AppEnvironment == polygon.EnvironmentDevelopment {
+		if tree, ok := DebugTree(s.Span.Context.Tree()); ok {
+			c.Set(HeaderDebugTree, tree)
+		}
+	}
+
+	// * count metric
+	r.Instrument.HttpDurationRecord(s.Span.TracingContext, time.Now().Sub(*s.Started()).Milliseconds(), method, route, c.Response().StatusCode())
+	s.Span.TracingSpan.SetAttributes(r.HttpResponseAttributes(c, route)...)
+}
+
+func DebugTree(tree *span.Tree) (string, bool) {
+	if tree == nil {
+		return "", false
+	}
+
+	// * search deepest level whose encoded tree fits header limit
+	fitted := ""
+	low, high := 0, tree.Depth()
+	for low <= high {
+		depth := (low + high) / 2
+		encoded, err := tree.Prune(depth).Json()
+		if err != nil {
+			return "", false
+		}
+		if len(encoded) <= HeaderDebugTreeLimit {
+			fitted = string(encoded)
+			low = depth + 1
+		} else {
+			high = depth - 1
+		}
+	}
+	return fitted, fitted != ""
+}
+
+func SpanFromCtx(c fiber.Ctx) polygon.Span {
+	s, ok := c.Locals(localSpanKey{}).(polygon.Span)
+	if !ok {
+		return nil
+	}
+	return s
+}
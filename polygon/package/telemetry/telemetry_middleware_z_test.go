@@ -0,0 +1,198 @@
+package telemetry_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestMiddlewareRenameRace(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	// Fork from a background goroutine while the request finishes
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	app := fiber.New()
+	app.Use(recorder.TracerMiddleware())
+	app.Get("/users/:id", func(c fiber.Ctx) error {
+		s := telemetry.SpanFromCtx(c)
+		started := make(chan struct{})
+		wg.Go(func() {
+			for i := 0; ; i++ {
+				child := s.Fork("worker")
+				child.Log(slog.LevelDebug, "working")
+				child.End()
+				if i == 0 {
+					close(started)
+				}
+				select {
+				case <-stop:
+					return
+				default:
+				}
+			}
+		})
+		<-started
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/users/42", nil)); err != nil {
+		t.Fatalf("Failed request: %v", err)
+	}
+	close(stop)
+	wg.Wait()
+
+	ended := recorder.Ended("HTTP GET /users/:id")
+	if ended == nil {
+		t.Fatal("Expected request span renamed to route template")
+	}
+	for _, child := range recorder.Spans.Ended() {
+		if child.SpanKind() == ended.SpanKind() {
+			continue
+		}
+		if !strings.HasPrefix(child.Name(), "HTTP GET") {
+			t.Errorf("Expected forked span named under request, got %s", child.Name())
+		}
+	}
+}
+
+func TestMiddlewareServerSpan(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) (err error) {
+		defer func() {
+			if recover() != nil {
+				err = fiber.ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	})
+	app.Use(recorder.TracerMiddleware())
+	app.Get("/ok", func(c fiber.Ctx) error {
+		telemetry.SpanFromCtx(c).Fork("service").End()
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Post("/panic", func(c fiber.Ctx) error {
+		panic("boom")
+	})
+
+	cases := []struct {
+		method string
+		path   string
+		name   string
+		status int
+	}{
+		{http.MethodGet, "/ok", "HTTP GET /ok", http.StatusNoContent},
+		{http.MethodPost, "/panic", "HTTP POST /panic", http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		recorder.Spans.Reset()
+		res, err := app.Test(httptest.NewRequest(c.method, c.path, nil))
+		if err != nil {
+			t.Fatalf("Failed request %s: %v", c.path, err)
+		}
+		if res.StatusCode != c.status {
+			t.Errorf("Expected status %d for %s, got %d", c.status, c.path, res.StatusCode)
+		}
+
+		// Exactly one server span wraps the request
+		servers := make([]string, 0)
+		for _, ended := range recorder.Spans.Ended() {
+			if ended.SpanKind() == trace.SpanKindServer {
+				servers = append(servers, ended.Name())
+			}
+		}
+		if len(servers) != 1 || servers[0] != c.name {
+			t.Errorf("Expected single server span %s, got %v", c.name, servers)
+		}
+	}
+	if ended := recorder.Ended("HTTP POST /panic"); ended == nil || ended.Status().Code != codes.Error {
+		t.Errorf("Expected panicking request span to fail, got %v", ended)
+	}
+
+	// Active requests return to zero for every method
+	points := recorder.Sum("app.http.active_requests")
+	if len(points) != 2 {
+		t.Fatalf("Expected active requests per method, got %v", points)
+	}
+	for _, point := range points {
+		method, _ := point.Attributes.Value(semconv.HTTPRequestMethodKey)
+		if point.Value != 0 {
+			t.Errorf("Expected no active %s requests, got %d", method.AsString(), point.Value)
+		}
+	}
+}
+
+func TestMiddlewareDebugTree(t *testing.T) {
+	environment := polygon.EnvironmentDevelopment
+	recorder, err := telemetrytest.New(&polygon.Config{AppEnvironment: &environment})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(recorder.TracerMiddleware())
+	app.Get("/tree/:depth", func(c fiber.Ctx) error {
+		s := telemetry.SpanFromCtx(c)
+		depth := fiber.Params[int](c, "depth")
+		for i := range depth {
+			s = s.Fork(fmt.Sprintf("layer-%d", i))
+			s.Variable("payload", strings.Repeat("x", 64))
+			defer s.End()
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	cases := []struct {
+		depth     int
+		truncated bool
+	}{
+		{2, false},
+		{40, true},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/tree/%d", c.depth), nil)
+		req.Header.Set(telemetry.HeaderDebug, "1")
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed request: %v", err)
+		}
+
+		header := res.Header.Get(telemetry.HeaderDebugTree)
+		if header == "" || len(header) > telemetry.HeaderDebugTreeLimit {
+			t.Fatalf("Expected tree header within %d bytes, got %d", telemetry.HeaderDebugTreeLimit, len(header))
+		}
+		tree := new(span.Tree)
+		if err := json.Unmarshal([]byte(header), tree); err != nil {
+			t.Fatalf("Expected valid tree json, got %v", err)
+		}
+		truncated := false
+		tree.Walk(func(node *span.Tree, depth int) {
+			truncated = truncated || node.Truncated != nil
+		})
+		if truncated != c.truncated || (!c.truncated && tree.Depth() != c.depth) {
+			t.Errorf("Expected depth %d truncated %v, got depth %d truncated %v", c.depth, c.truncated, tree.Depth(), truncated)
+		}
+	}
+}
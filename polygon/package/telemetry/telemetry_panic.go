@@ -0,0 +1,34 @@
+package telemetry
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.scnd.dev/open/polygon"
+)
+
+type PanicError struct {
+	Value any
+	Stack string
+}
+
+func NewPanicError(value any) *PanicError {
+	return &PanicError{
+		Value: value,
+		Stack: string(debug.Stack()),
+	}
+}
+
+func (r *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", r.Value)
+}
+
+func RecordPanic(s polygon.Span, panicErr *PanicError) error {
+	s.Event("panic",
+		semconv.ExceptionType(fmt.Sprintf("%T", panicErr.Value)),
+		semconv.ExceptionMessage(panicErr.Error()),
+		semconv.ExceptionStacktrace(panicErr.Stack),
+	)
+	return s.ErrorDimension(polygon.DimensionTypeFatal, polygon.DimensionScopeProcedure, "panic recovered", panicErr)
+}
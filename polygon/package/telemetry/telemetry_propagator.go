@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+func NewPropagator(telemetry *Telemetry) (propagation.TextMapPropagator, error) {
+	// * resolve propagator names
+	names := telemetry.Polygon.Config().TelemetryPropagators
+	if len(names) == 0 {
+		names = []polygon.TelemetryPropagator{
+			polygon.TelemetryPropagatorTraceContext,
+			polygon.TelemetryPropagatorBaggage,
+		}
+	}
+
+	// * construct propagators
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case polygon.TelemetryPropagatorTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case polygon.TelemetryPropagatorBaggage:
+			propagators = append(propagators, propagation.Baggage{})
+		case polygon.TelemetryPropagatorB3:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case polygon.TelemetryPropagatorB3Multi:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case polygon.TelemetryPropagatorJaeger:
+			propagators = append(propagators, jaeger.Jaeger{})
+		default:
+			return nil, span.NewError(nil, fmt.Sprintf("unknown telemetry propagator %s", name), nil)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+type HeaderCarrier struct {
+	Ctx fiber.Ctx
+}
+
+func (r *HeaderCarrier) Get(key string) string {
+	return r.Ctx.Get(key)
+}
+
+func (r *HeaderCarrier) Set(key string, value string) {
+	r.Ctx.Request().Header.Set(key, value)
+}
+
+func (r *HeaderCarrier) Keys() []string {
+	headers := r.Ctx.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,97 @@
+package telemetry_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestPropagatorSelection(t *testing.T) {
+	cases := []struct {
+		names  []polygon.TelemetryPropagator
+		fields []string
+		err    bool
+	}{
+		{nil, []string{"traceparent", "tracestate", "baggage"}, false},
+		{[]polygon.TelemetryPropagator{polygon.TelemetryPropagatorB3}, []string{"b3"}, false},
+		{[]polygon.TelemetryPropagator{polygon.TelemetryPropagatorB3Multi}, []string{"x-b3-traceid", "x-b3-spanid", "x-b3-sampled"}, false},
+		{[]polygon.TelemetryPropagator{polygon.TelemetryPropagatorJaeger}, []string{"uber-trace-id"}, false},
+		{[]polygon.TelemetryPropagator{polygon.TelemetryPropagatorTraceContext, "zipkin"}, nil, true},
+	}
+	for _, c := range cases {
+		recorder, err := telemetrytest.New(&polygon.Config{TelemetryPropagators: c.names})
+		if err != nil {
+			t.Fatalf("Failed to create recorder: %v", err)
+		}
+		propagator, err := telemetry.NewPropagator(&telemetry.Telemetry{Polygon: recorder})
+		if c.err {
+			if err == nil {
+				t.Errorf("Expected unknown propagator in %v to fail", c.names)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Failed to create propagator %v: %v", c.names, err)
+		}
+		for _, field := range c.fields {
+			if !slices.Contains(propagator.Fields(), field) {
+				t.Errorf("Expected %v propagator to use %s, got %v", c.names, field, propagator.Fields())
+			}
+		}
+	}
+}
+
+func TestPropagatorExtract(t *testing.T) {
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	cases := []struct {
+		propagator polygon.TelemetryPropagator
+		header     string
+		value      string
+	}{
+		{polygon.TelemetryPropagatorTraceContext, "traceparent", "00-" + remote.TraceID().String() + "-" + remote.SpanID().String() + "-01"},
+		{polygon.TelemetryPropagatorB3, "b3", remote.TraceID().String() + "-" + remote.SpanID().String() + "-1"},
+		{polygon.TelemetryPropagatorJaeger, "uber-trace-id", remote.TraceID().String() + ":" + remote.SpanID().String() + ":0:1"},
+	}
+	for _, c := range cases {
+		recorder, err := telemetrytest.New(&polygon.Config{TelemetryPropagators: []polygon.TelemetryPropagator{c.propagator}})
+		if err != nil {
+			t.Fatalf("Failed to create recorder: %v", err)
+		}
+		propagator, err := telemetry.NewPropagator(recorder.Telemetry)
+		if err != nil {
+			t.Fatalf("Failed to create propagator: %v", err)
+		}
+		otel.SetTextMapPropagator(propagator)
+
+		app := fiber.New()
+		app.Use(recorder.TracerMiddleware())
+		app.Get("/", func(c fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(c.header, c.value)
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("Failed request: %v", err)
+		}
+
+		ended := recorder.Ended("HTTP GET /")
+		if ended == nil {
+			t.Fatalf("%s: expected ended server span", c.propagator)
+		}
+		if ended.SpanContext().TraceID() != remote.TraceID() || ended.Parent().SpanID() != remote.SpanID() || !ended.Parent().IsRemote() {
+			t.Errorf("%s: expected remote parent %s/%s, got %s/%s", c.propagator, remote.TraceID(), remote.SpanID(), ended.SpanContext().TraceID(), ended.Parent().SpanID())
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/bsthun/gut"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+var registryNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_./-]{0,254}$`)
+
+type RegistryKind string
+
+const (
+	RegistryKindCounter       RegistryKind = "counter"
+	RegistryKindUpDownCounter RegistryKind = "updowncounter"
+	RegistryKindHistogram     RegistryKind = "histogram"
+	RegistryKindGauge         RegistryKind = "gauge"
+)
+
+type Registry struct {
+	Meter   metric.Meter
+	Mutex   sync.Mutex
+	Entries map[string]*RegistryEntry
+}
+
+type RegistryEntry struct {
+	Kind       RegistryKind
+	Definition *polygon.InstrumentDefinition
+	Instrument any
+}
+
+type RegistryCounter struct {
+	Entry   *RegistryEntry
+	Counter metric.Int64Counter
+}
+
+type RegistryUpDownCounter struct {
+	Entry         *RegistryEntry
+	UpDownCounter metric.Int64UpDownCounter
+}
+
+type RegistryHistogram struct {
+	Entry     *RegistryEntry
+	Histogram metric.Float64Histogram
+}
+
+func NewRegistry(meter metric.Meter) *Registry {
+	return &Registry{
+		Meter:   meter,
+		Entries: make(map[string]*RegistryEntry),
+	}
+}
+
+func (r *Registry) Counter(definition *polygon.InstrumentDefinition) (polygon.Counter, error) {
+	entry, err := r.Register(RegistryKindCounter, definition, func() (any, error) {
+		options := make([]metric.Int64CounterOption, 0)
+		if definition.Description != nil {
+			options = append(options, metric.WithDescription(*definition.Description))
+		}
+		if definition.Unit != nil {
+			options = append(options, metric.WithUnit(*definition.Unit))
+		}
+		counter, err := r.Meter.Int64Counter(*definition.Name, options...)
+		if err != nil {
+			return nil, err
+		}
+		return &RegistryCounter{Counter: counter}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry.Instrument.(*RegistryCounter), nil
+}
+
+func (r *Registry) UpDownCounter(definition *polygon.InstrumentDefinition) (polygon.UpDownCounter, error) {
+	entry, err := r.Register(RegistryKindUpDownCounter, definition, func() (any, error) {
+		options := make([]metric.Int64UpDownCounterOption, 0)
+		if definition.Description != nil {
+			options = append(options, metric.WithDescription(*definition.Description))
+		}
+		if definition.Unit != nil {
+			options = append(options, metric.WithUnit(*definition.Unit))
+		}
+		counter, err := r.Meter.Int64UpDownCounter(*definition.Name, options...)
+		if err != nil {
+			return nil, err
+		}
+		return &RegistryUpDownCounter{UpDownCounter: counter}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry.Instrument.(*RegistryUpDownCounter), nil
+}
+
+func (r *Registry) Histogram(definition *polygon.InstrumentDefinition) (polygon.Histogram, error) {
+	entry, err := r.Register(RegistryKindHistogram, definition, func() (any, error) {
+		options := make([]metric.Float64HistogramOption, 0)
+		if definition.Description != nil {
+			options = append(options, metric.WithDescription(*definition.Description))
+		}
+		if definition.Unit != nil {
+			options = append(options, metric.WithUnit(*definition.Unit))
+		}
+		if len(definition.Buckets) > 0 {
+			options = append(options, metric.WithExplicitBucketBoundaries(definition.Buckets...))
+		}
+		histogram, err := r.Meter.Float64Histogram(*definition.Name, options...)
+		if err != nil {
+			return nil, err
+		}
+		return &RegistryHistogram{Histogram: histogram}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry.Instrument.(*RegistryHistogram), nil
+}
+
+func (r *Registry) Gauge(definition *polygon.InstrumentDefinition, callback polygon.GaugeCallback) error {
+	if callback == nil {
+		return span.NewError(nil, "gauge callback is required", nil)
+	}
+
+	_, err := r.Register(RegistryKindGauge, definition, func() (any, error) {
+		options := make([]metric.Float64ObservableGaugeOption, 0)
+		if definition.Description != nil {
+			options = append(options, metric.WithDescription(*definition.Description))
+		}
+		if definition.Unit != nil {
+			options = append(options, metric.WithUnit(*definition.Unit))
+		}
+		validator := &RegistryEntry{
+			Kind:       RegistryKindGauge,
+			Definition: definition,
+		}
+		options = append(options, metric.WithFloat64Callback(func(ctx context.Context, observer metric.Float64Observer) error {
+			return callback(ctx, func(value float64, attributes ...attribute.KeyValue) error {
+				if err := validator.Validate(attributes); err != nil {
+					return err
+				}
+				observer.Observe(value, metric.WithAttributes(attributes...))
+				return nil
+			})
+		}))
+		return r.Meter.Float64ObservableGauge(*definition.Name, options...)
+	})
+	return err
+}
+
+func (r *Registry) Register(kind RegistryKind, definition *polygon.InstrumentDefinition, construct func() (any, error)) (*RegistryEntry, error) {
+	if definition == nil || definition.Name == nil {
+		return nil, span.NewError(nil, "instrument name is required", nil)
+	}
+	if !registryNameRegex.MatchString(*definition.Name) {
+		return nil, span.NewError(nil, fmt.Sprintf("invalid instrument name %s", *definition.Name), nil)
+	}
+
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	// * return cached instrument
+	if entry, ok := r.Entries[*definition.Name]; ok {
+		if entry.Kind != kind {
+			return nil, span.NewError(nil, fmt.Sprintf("instrument %s is already registered as %s", *definition.Name, entry.Kind), nil)
+		}
+		if kind == RegistryKindGauge {
+			return nil, span.NewError(nil, fmt.Sprintf("gauge %s is already registered", *definition.Name), nil)
+		}
+		if !entry.Match(definition) {
+			return nil, span.NewError(nil, fmt.Sprintf("instrument %s is already registered with different definition", *definition.Name), nil)
+		}
+		return entry, nil
+	}
+
+	// * construct instrument
+	instrument, err := construct()
+	if err != nil {
+		return nil, span.NewError(nil, fmt.Sprintf("unable to construct instrument %s", *definition.Name), err)
+	}
+	entry := &RegistryEntry{
+		Kind:       kind,
+		Definition: definition,
+		Instrument: instrument,
+	}
+	switch instrument := instrument.(type) {
+	case *RegistryCounter:
+		instrument.Entry = entry
+	case *RegistryUpDownCounter:
+		instrument.Entry = entry
+	case *RegistryHistogram:
+		instrument.Entry = entry
+	}
+	r.Entries[*definition.Name] = entry
+
+	return entry, nil
+}
+
+func (r *RegistryEntry) Match(definition *polygon.InstrumentDefinition) bool {
+	return gut.Val(r.Definition.Description) == gut.Val(definition.Description) &&
+		gut.Val(r.Definition.Unit) == gut.Val(definition.Unit) &&
+		slices.Equal(r.Definition.Buckets, definition.Buckets) &&
+		slices.Equal(r.Definition.Attributes, definition.Attributes)
+}
+
+func (r *RegistryEntry) Validate(attributes []attribute.KeyValue) error {
+	seen := make(map[attribute.Key]struct{}, len(attributes))
+	for _, attr := range attributes {
+		if !attr.Valid() {
+			return span.NewError(nil, fmt.Sprintf("invalid attribute %s on instrument %s", attr.Key, *r.Definition.Name), nil)
+		}
+		if !slices.Contains(r.Definition.Attributes, string(attr.Key)) {
+			return span.NewError(nil, fmt.Sprintf("undeclared attribute %s on instrument %s", attr.Key, *r.Definition.Name), nil)
+		}
+		if _, ok := seen[attr.Key]; ok {
+			return span.NewError(nil, fmt.Sprintf("duplicate attribute %s on instrument %s", attr.Key, *r.Definition.Name), nil)
+		}
+		seen[attr.Key] = struct{}{}
+	}
+	return nil
+}
+
+func (r *RegistryCounter) Add(ctx context.Context, value int64, attributes ...attribute.KeyValue) error {
+	if err := r.Entry.Validate(attributes); err != nil {
+		return err
+	}
+	if value < 0 {
+		return span.NewError(nil, fmt.Sprintf("negative increment on counter %s", *r.Entry.Definition.Name), nil)
+	}
+	r.Counter.Add(ctx, value, metric.WithAttributes(attributes...))
+	return nil
+}
+
+func (r *RegistryUpDownCounter) Add(ctx context.Context, value int64, attributes ...attribute.KeyValue) error {
+	if err := r.Entry.Validate(attributes); err != nil {
+		return err
+	}
+	r.UpDownCounter.Add(ctx, value, metric.WithAttributes(attributes...))
+	return nil
+}
+
+func (r *RegistryHistogram) Record(ctx context.Context, value float64, attributes ...attribute.KeyValue) error {
+	if err := r.Entry.Validate(attributes); err != nil {
+		return err
+	}
+	r.Histogram.Record(ctx, value, metric.WithAttributes(attributes...))
+	return nil
+}
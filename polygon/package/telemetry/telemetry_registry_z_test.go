@@ -0,0 +1,77 @@
+package telemetry_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestRegistry(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	instrument := recorder.Instrument()
+	definition := func(name string, unit string) *polygon.InstrumentDefinition {
+		return &polygon.InstrumentDefinition{
+			Name:       &name,
+			Unit:       &unit,
+			Attributes: []string{"order.channel"},
+		}
+	}
+	expectError := func(err error, contains string) {
+		t.Helper()
+		if err == nil || !strings.Contains(err.Error(), contains) {
+			t.Errorf("Expected error containing %q, got %v", contains, err)
+		}
+	}
+
+	// Invalid names are rejected before construction
+	for _, name := range []string{"", "1orders", "orders total", strings.Repeat("a", 256)} {
+		_, err := instrument.Counter(definition(name, "1"))
+		expectError(err, "invalid instrument name")
+	}
+	_, err = instrument.Counter(&polygon.InstrumentDefinition{})
+	expectError(err, "instrument name is required")
+
+	// Second registration returns the cached instrument
+	first, err := instrument.Counter(definition("shop.orders", "1"))
+	if err != nil {
+		t.Fatalf("Failed to register counter: %v", err)
+	}
+	second, err := instrument.Counter(definition("shop.orders", "1"))
+	if err != nil || second != first {
+		t.Errorf("Expected cached counter, got %v (%v)", second, err)
+	}
+
+	// Same name with another kind or definition is rejected
+	_, err = instrument.Histogram(definition("shop.orders", "1"))
+	expectError(err, "already registered as counter")
+	_, err = instrument.Counter(definition("shop.orders", "ms"))
+	expectError(err, "different definition")
+	gauge := func(ctx context.Context, observe polygon.GaugeObserver) error {
+		return nil
+	}
+	if err := instrument.Gauge(definition("shop.queue", "1"), gauge); err != nil {
+		t.Fatalf("Failed to register gauge: %v", err)
+	}
+	expectError(instrument.Gauge(definition("shop.queue", "1"), gauge), "gauge shop.queue is already registered")
+
+	// Attributes must be declared and counters only grow
+	ctx := context.Background()
+	if err := first.Add(ctx, 2, attribute.String("order.channel", "web")); err != nil {
+		t.Errorf("Expected declared attribute to be accepted, got %v", err)
+	}
+	expectError(first.Add(ctx, 1, attribute.String("user.id", "42")), "undeclared attribute user.id")
+	expectError(first.Add(ctx, 1, attribute.String("order.channel", "web"), attribute.String("order.channel", "app")), "duplicate attribute")
+	expectError(first.Add(ctx, -1, attribute.String("order.channel", "web")), "negative increment")
+
+	points := recorder.Sum("shop.orders")
+	if len(points) != 1 || points[0].Value != 2 {
+		t.Errorf("Expected only the valid increment to be recorded, got %v", points)
+	}
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"runtime"
+
+	"go.opentelemetry.io/contrib/instrumentation/host"
+	otelruntime "go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel/metric"
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+func NewRuntime(telemetry *Telemetry) error {
+	// * start go runtime collector
+	if err := otelruntime.Start(otelruntime.WithMeterProvider(telemetry.MeterProvider)); err != nil {
+		return span.NewError(nil, "unable to start runtime metric collector", err)
+	}
+
+	// * start host collector
+	if err := host.Start(host.WithMeterProvider(telemetry.MeterProvider)); err != nil {
+		return span.NewError(nil, "unable to start host metric collector", err)
+	}
+
+	// * construct process instruments
+	meter := telemetry.MeterProvider.Meter("polygon-runtime")
+	gcPause, err := meter.Float64ObservableCounter(
+		"go.gc.pause.duration",
+		metric.WithDescription("Cumulative stop-the-world pause duration of garbage collection"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return span.NewError(nil, "unable to initialize gc pause instrument", err)
+	}
+	gcCount, err := meter.Int64ObservableCounter(
+		"go.gc.count",
+		metric.WithDescription("Number of completed garbage collection cycles"),
+	)
+	if err != nil {
+		return span.NewError(nil, "unable to initialize gc count instrument", err)
+	}
+	instruments := []metric.Observable{gcPause, gcCount}
+
+	// * construct descriptor instrument where procfs is available
+	var fdCount metric.Int64ObservableUpDownCounter
+	if runtime.GOOS == "linux" {
+		fdCount, err = meter.Int64ObservableUpDownCounter(
+			"process.open_file_descriptor.count",
+			metric.WithDescription("Number of file descriptors in use by the process"),
+		)
+		if err != nil {
+			return span.NewError(nil, "unable to initialize file descriptor instrument", err)
+		}
+		instruments = append(instruments, fdCount)
+	}
+
+	// * register process callback
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		stats := new(runtime.MemStats)
+		runtime.ReadMemStats(stats)
+		observer.ObserveFloat64(gcPause, float64(stats.PauseTotalNs)/1e9)
+		observer.ObserveInt64(gcCount, int64(stats.NumGC))
+
+		// * count descriptors
+		if fdCount != nil {
+			if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+				observer.ObserveInt64(fdCount, int64(len(entries)))
+			}
+		}
+		return nil
+	}, instruments...)
+	if err != nil {
+		return span.NewError(nil, "unable to register process metric callback", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package telemetry_test
+
+import (
+	"runtime"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestRuntime(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	if err := telemetry.NewRuntime(recorder.Telemetry); err != nil {
+		t.Fatalf("Failed to start runtime collector: %v", err)
+	}
+	runtime.GC()
+
+	// Process callback observes garbage collection
+	if points := recorder.Sum("go.gc.count"); len(points) != 1 || points[0].Value < 1 {
+		t.Errorf("Expected gc count after forced collection, got %v", points)
+	}
+	if pause, ok := recorder.Metric("go.gc.pause.duration").(metricdata.Sum[float64]); !ok || len(pause.DataPoints) != 1 {
+		t.Errorf("Expected gc pause duration, got %v", recorder.Metric("go.gc.pause.duration"))
+	}
+
+	// Descriptors are only reported where procfs exists
+	points := recorder.Sum("process.open_file_descriptor.count")
+	if runtime.GOOS == "linux" && (len(points) != 1 || points[0].Value < 1) {
+		t.Errorf("Expected open descriptor count on linux, got %v", points)
+	}
+	if runtime.GOOS != "linux" && points != nil {
+		t.Errorf("Expected no descriptor count on %s, got %v", runtime.GOOS, points)
+	}
+
+	// Contributed runtime and host collectors share the provider
+	if recorder.Metric("go.goroutine.count") == nil {
+		t.Error("Expected go runtime metrics")
+	}
+}
@@ -0,0 +1,160 @@
+package telemetry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+)
+
+type Sampler struct {
+	Ratio   sdktrace.Sampler
+	Limiter *SamplerLimiter
+	Rules   []*SamplerRule
+}
+
+type SamplerRule struct {
+	Rule    *polygon.TelemetrySamplingRule
+	Sampler sdktrace.Sampler
+}
+
+type SamplerLimiter struct {
+	Mutex   sync.Mutex
+	Rate    float64
+	Burst   float64
+	Tokens  float64
+	Updated time.Time
+}
+
+func NewSampler(sampling *polygon.TelemetrySampling) *Sampler {
+	sampler := &Sampler{
+		Ratio:   sdktrace.AlwaysSample(),
+		Limiter: nil,
+		Rules:   make([]*SamplerRule, 0),
+	}
+	if sampling == nil {
+		return sampler
+	}
+
+	// * construct root ratio
+	if sampling.Ratio != nil {
+		sampler.Ratio = sdktrace.TraceIDRatioBased(*sampling.Ratio)
+	}
+
+	// * construct rate limiter
+	if sampling.RateLimit != nil {
+		burst := math.Max(1, *sampling.RateLimit)
+		sampler.Limiter = &SamplerLimiter{
+			Rate:    *sampling.RateLimit,
+			Burst:   burst,
+			Tokens:  burst,
+			Updated: time.Now(),
+		}
+	}
+
+	// * construct rules
+	for _, rule := range sampling.Rules {
+		ratio := 1.0
+		if rule.Ratio != nil {
+			ratio = *rule.Ratio
+		}
+		sampler.Rules = append(sampler.Rules, &SamplerRule{
+			Rule:    rule,
+			Sampler: sdktrace.TraceIDRatioBased(ratio),
+		})
+	}
+
+	return sampler
+}
+
+func (r *Sampler) ShouldSample(parameters sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(parameters.ParentContext)
+
+	// * drop children of unsampled parent
+	if parent.IsValid() && !parent.IsSampled() {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.Drop,
+			Tracestate: parent.TraceState(),
+		}
+	}
+
+	// * apply first matching rule
+	for _, rule := range r.Rules {
+		if rule.Match(parameters.Attributes) {
+			return rule.Sampler.ShouldSample(parameters)
+		}
+	}
+
+	// * follow sampled parent
+	if parent.IsValid() {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: parent.TraceState(),
+		}
+	}
+
+	// * apply root ratio and rate limit
+	result := r.Ratio.ShouldSample(parameters)
+	if result.Decision == sdktrace.RecordAndSample && r.Limiter != nil && !r.Limiter.Allow() {
+		result.Decision = sdktrace.Drop
+	}
+
+	return result
+}
+
+func (r *Sampler) Description() string {
+	return fmt.Sprintf("PolygonSampler{ratio=%s,rules=%d}", r.Ratio.Description(), len(r.Rules))
+}
+
+func (r *SamplerRule) Match(attributes []attribute.KeyValue) bool {
+	if r.Rule.Layer == nil && r.Rule.Path == nil {
+		return false
+	}
+
+	layerMatched := r.Rule.Layer == nil
+	pathMatched := r.Rule.Path == nil
+	for _, attr := range attributes {
+		switch attr.Key {
+		case "span.layer":
+			if r.Rule.Layer != nil && attr.Value.AsString() == *r.Rule.Layer {
+				layerMatched = true
+			}
+		case semconv.URLPathKey, semconv.HTTPRouteKey:
+			if r.Rule.Path != nil && MatchPath(*r.Rule.Path, attr.Value.AsString()) {
+				pathMatched = true
+			}
+		}
+	}
+
+	return layerMatched && pathMatched
+}
+
+func (r *SamplerLimiter) Allow() bool {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	// * refill tokens
+	now := time.Now()
+	r.Tokens = math.Min(r.Burst, r.Tokens+now.Sub(r.Updated).Seconds()*r.Rate)
+	r.Updated = now
+
+	if r.Tokens < 1 {
+		return false
+	}
+	r.Tokens--
+	return true
+}
+
+func MatchPath(pattern string, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return pattern == path
+}
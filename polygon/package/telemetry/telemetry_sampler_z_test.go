@@ -0,0 +1,133 @@
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsthun/gut"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+var (
+	traceLow  = trace.TraceID{0x01}
+	traceHigh = trace.TraceID{0x01, 8: 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+)
+
+func parentContext(sampled bool) context.Context {
+	flags := trace.TraceFlags(0)
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceLow,
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: flags,
+		Remote:     true,
+	}))
+}
+
+func TestSamplerDecision(t *testing.T) {
+	rule := func(layer *string, path *string, ratio float64) *polygon.TelemetrySamplingRule {
+		return &polygon.TelemetrySamplingRule{Layer: layer, Path: path, Ratio: &ratio}
+	}
+	layer := func(value string) attribute.KeyValue {
+		return attribute.String("span.layer", value)
+	}
+
+	cases := []struct {
+		name       string
+		sampling   *polygon.TelemetrySampling
+		parent     context.Context
+		traceId    trace.TraceID
+		attributes []attribute.KeyValue
+		expected   sdktrace.SamplingDecision
+	}{
+		{"default root", nil, context.Background(), traceHigh, nil, sdktrace.RecordAndSample},
+		{"unsampled parent beats rule", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(gut.Ptr("database"), nil, 1)}}, parentContext(false), traceLow, []attribute.KeyValue{layer("database")}, sdktrace.Drop},
+		{"sampled parent beats root ratio", &polygon.TelemetrySampling{Ratio: gut.Ptr(0.0)}, parentContext(true), traceLow, nil, sdktrace.RecordAndSample},
+		{"rule beats sampled parent", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(nil, gut.Ptr("/health"), 0)}}, parentContext(true), traceLow, []attribute.KeyValue{semconv.URLPath("/health")}, sdktrace.Drop},
+		{"first rule wins drop", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(gut.Ptr("database"), nil, 0), rule(gut.Ptr("database"), nil, 1)}}, context.Background(), traceLow, []attribute.KeyValue{layer("database")}, sdktrace.Drop},
+		{"first rule wins sample", &polygon.TelemetrySampling{Ratio: gut.Ptr(0.0), Rules: []*polygon.TelemetrySamplingRule{rule(gut.Ptr("database"), nil, 1), rule(gut.Ptr("database"), nil, 0)}}, context.Background(), traceHigh, []attribute.KeyValue{layer("database")}, sdktrace.RecordAndSample},
+		{"prefix matches route", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(nil, gut.Ptr("/api/*"), 0)}}, context.Background(), traceLow, []attribute.KeyValue{semconv.HTTPRoute("/api/users/:id")}, sdktrace.Drop},
+		{"prefix misses sibling", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(nil, gut.Ptr("/api/*"), 0)}}, context.Background(), traceLow, []attribute.KeyValue{semconv.URLPath("/apiv2/users")}, sdktrace.RecordAndSample},
+		{"exact path misses prefix", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(nil, gut.Ptr("/api"), 0)}}, context.Background(), traceLow, []attribute.KeyValue{semconv.URLPath("/api/users")}, sdktrace.RecordAndSample},
+		{"layer and path both required", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(gut.Ptr("handler"), gut.Ptr("/health"), 0)}}, context.Background(), traceLow, []attribute.KeyValue{layer("handler"), semconv.URLPath("/users")}, sdktrace.RecordAndSample},
+		{"empty rule never matches", &polygon.TelemetrySampling{Rules: []*polygon.TelemetrySamplingRule{rule(nil, nil, 0)}}, context.Background(), traceLow, []attribute.KeyValue{layer("handler")}, sdktrace.RecordAndSample},
+		{"root ratio keeps low trace", &polygon.TelemetrySampling{Ratio: gut.Ptr(0.5)}, context.Background(), traceLow, nil, sdktrace.RecordAndSample},
+		{"root ratio drops high trace", &polygon.TelemetrySampling{Ratio: gut.Ptr(0.5)}, context.Background(), traceHigh, nil, sdktrace.Drop},
+	}
+	for _, c := range cases {
+		result := telemetry.NewSampler(c.sampling).ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: c.parent,
+			TraceID:       c.traceId,
+			Name:          c.name,
+			Kind:          trace.SpanKindInternal,
+			Attributes:    c.attributes,
+		})
+		if result.Decision != c.expected {
+			t.Errorf("%s: expected decision %v, got %v", c.name, c.expected, result.Decision)
+		}
+	}
+}
+
+func TestSamplerLimiter(t *testing.T) {
+	sampler := telemetry.NewSampler(&polygon.TelemetrySampling{RateLimit: gut.Ptr(2.0)})
+	decide := func(parent context.Context) sdktrace.SamplingDecision {
+		return sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: parent, TraceID: traceLow}).Decision
+	}
+
+	// Burst allows two roots, the third is dropped
+	expected := []sdktrace.SamplingDecision{sdktrace.RecordAndSample, sdktrace.RecordAndSample, sdktrace.Drop}
+	for i, decision := range expected {
+		if result := decide(context.Background()); result != decision {
+			t.Errorf("Expected root %d decision %v, got %v", i, decision, result)
+		}
+	}
+
+	// Children of sampled parents do not consume tokens
+	if result := decide(parentContext(true)); result != sdktrace.RecordAndSample {
+		t.Errorf("Expected child of sampled parent to bypass limiter, got %v", result)
+	}
+
+	// Tokens refill at the configured rate
+	sampler.Limiter.Mutex.Lock()
+	sampler.Limiter.Updated = sampler.Limiter.Updated.Add(-time.Second)
+	sampler.Limiter.Mutex.Unlock()
+	for i := range 2 {
+		if result := decide(context.Background()); result != sdktrace.RecordAndSample {
+			t.Errorf("Expected refilled root %d to be sampled, got %v", i, result)
+		}
+	}
+	if result := decide(context.Background()); result != sdktrace.Drop {
+		t.Errorf("Expected limiter to drop after refill is spent, got %v", result)
+	}
+}
+
+func TestSamplerRateLimitedSpan(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{
+		TelemetrySampling: &polygon.TelemetrySampling{RateLimit: gut.Ptr(1.0)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	first := recorder.Span(context.Background(), "first", "test", nil)
+	first.End()
+	second := recorder.Span(context.Background(), "second", "test", nil)
+	second.Fork("child").End()
+	second.End()
+
+	if recorder.Ended("first") == nil {
+		t.Error("Expected first root span to be recorded")
+	}
+	if len(recorder.Spans.Ended()) != 1 {
+		t.Errorf("Expected rate limited root and its child to be dropped, got %d spans", len(recorder.Spans.Ended()))
+	}
+}
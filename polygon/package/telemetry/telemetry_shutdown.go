@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.scnd.dev/open/polygon/package/span"
+)
+
+const ShutdownTimeout = 10 * time.Second
+
+func (r *Telemetry) ForceFlush(ctx context.Context) error {
+	ctx, cancel := r.deadline(ctx)
+	defer cancel()
+
+	errs := make([]error, 0)
+
+	// * flush tracer provider
+	if r.TracerProvider != nil {
+		if err := r.TracerProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, span.NewError(nil, fmt.Sprintf("unable to flush %s trace exporter", r.Exporter()), err))
+		}
+	}
+
+	// * flush meter provider
+	if r.MeterProvider != nil {
+		if err := r.MeterProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, span.NewError(nil, fmt.Sprintf("unable to flush %s metric exporter", r.Exporter()), err))
+		}
+	}
+
+	// * flush logger provider
+	if r.LoggerProvider != nil {
+		if err := r.LoggerProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, span.NewError(nil, fmt.Sprintf("unable to flush %s log exporter", r.Exporter()), err))
+		}
+	}
+
+	// * sync shared file
+	if r.File != nil {
+		if err := r.File.Sync(); err != nil {
+			errs = append(errs, span.NewError(nil, "unable to sync telemetry file", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Telemetry) Shutdown(ctx context.Context) error {
+	ctx, cancel := r.deadline(ctx)
+	defer cancel()
+
+	errs := make([]error, 0)
+
+	// * shutdown tracer provider
+	if r.TracerProvider != nil {
+		if err := r.TracerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, span.NewError(nil, fmt.Sprintf("unable to shutdown %s trace exporter", r.Exporter()), err))
+		}
+	}
+
+	// * shutdown meter provider
+	if r.MeterProvider != nil {
+		if err := r.MeterProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, span.NewError(nil, fmt.Sprintf("unable to shutdown %s metric exporter", r.Exporter()), err))
+		}
+	}
+
+	// * shutdown logger provider
+	if r.LoggerProvider != nil {
+		if err := r.LoggerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, span.NewError(nil, fmt.Sprintf("unable to shutdown %s log exporter", r.Exporter()), err))
+		}
+	}
+
+	// * close shared file
+	if r.File != nil {
+		if err := r.File.Close(); err != nil {
+			errs = append(errs, span.NewError(nil, "unable to close telemetry file", err))
+		}
+		r.File = nil
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Telemetry) deadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, ShutdownTimeout)
+}
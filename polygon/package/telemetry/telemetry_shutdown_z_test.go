@@ -0,0 +1,120 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsthun/gut"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+type traceExporter struct {
+	shutdown error
+	deadline time.Time
+}
+
+func (r *traceExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return nil
+}
+
+func (r *traceExporter) Shutdown(ctx context.Context) error {
+	r.deadline, _ = ctx.Deadline()
+	return r.shutdown
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+	export   error
+	shutdown error
+}
+
+func (r *metricExporter) Export(ctx context.Context, metrics *metricdata.ResourceMetrics) error {
+	return r.export
+}
+
+func (r *metricExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (r *metricExporter) Shutdown(ctx context.Context) error {
+	return r.shutdown
+}
+
+func newShutdownTelemetry(t *testing.T, trace *traceExporter, metric *metricExporter) *telemetry.Telemetry {
+	recorder, err := telemetrytest.New(&polygon.Config{
+		TelemetryExporter: gut.Ptr(polygon.TelemetryExporterGrpc),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	metric.Exporter, err = stdoutmetric.New(stdoutmetric.WithWriter(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create metric exporter: %v", err)
+	}
+	return &telemetry.Telemetry{
+		Polygon:        recorder,
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(trace)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metric))),
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	errTrace := errors.New("trace collector unreachable")
+	errMetric := errors.New("metric collector unreachable")
+
+	// Only the failing provider is named in the error
+	trace := &traceExporter{shutdown: errTrace}
+	err := newShutdownTelemetry(t, trace, new(metricExporter)).Shutdown(context.Background())
+	if !errors.Is(err, errTrace) || err.Error() != "unable to shutdown grpc trace exporter: trace collector unreachable" {
+		t.Errorf("Expected wrapped trace exporter error, got %v", err)
+	}
+
+	// Default deadline applies when the caller has none
+	if remaining := time.Until(trace.deadline); remaining <= telemetry.ShutdownTimeout-time.Second || remaining > telemetry.ShutdownTimeout {
+		t.Errorf("Expected default %s deadline, got %s", telemetry.ShutdownTimeout, remaining)
+	}
+
+	// Caller deadline takes precedence
+	trace = new(traceExporter)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+	if err := newShutdownTelemetry(t, trace, new(metricExporter)).Shutdown(ctx); err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+	if !trace.deadline.Equal(expected) {
+		t.Errorf("Expected caller deadline %s, got %s", expected, trace.deadline)
+	}
+
+	// Failures from every provider are joined
+	err = newShutdownTelemetry(t, &traceExporter{shutdown: errTrace}, &metricExporter{shutdown: errMetric}).Shutdown(context.Background())
+	if !errors.Is(err, errTrace) || !errors.Is(err, errMetric) {
+		t.Errorf("Expected both exporter errors, got %v", err)
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 2 || !strings.Contains(lines[0], "trace exporter") || !strings.Contains(lines[1], "metric exporter") {
+		t.Errorf("Expected one line per failing exporter, got %q", err.Error())
+	}
+}
+
+func TestForceFlush(t *testing.T) {
+	errExport := errors.New("metric export rejected")
+
+	instance := newShutdownTelemetry(t, new(traceExporter), &metricExporter{export: errExport})
+	defer func() {
+		_ = instance.TracerProvider.Shutdown(context.Background())
+	}()
+	err := instance.ForceFlush(context.Background())
+	if !errors.Is(err, errExport) || !strings.HasPrefix(err.Error(), "unable to flush grpc metric exporter: ") {
+		t.Errorf("Expected wrapped metric flush error, got %v", err)
+	}
+}
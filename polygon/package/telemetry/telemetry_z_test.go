@@ -0,0 +1,66 @@
+package telemetry_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/telemetry/telemetrytest"
+)
+
+func TestHttpHeaders(t *testing.T) {
+	recorder, err := telemetrytest.New(&polygon.Config{
+		TelemetryHttp: &polygon.TelemetryHttp{
+			RequestHeaders:  []string{"x-request-id", "AUTHORIZATION", "Accept"},
+			ResponseHeaders: []string{"x-cache", "Set-Cookie"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(recorder.TracerMiddleware())
+	app.Get("/", func(c fiber.Ctx) error {
+		c.Set("X-Cache", "HIT")
+		c.Cookie(&fiber.Cookie{Name: "session", Value: "abc"})
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "42")
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "application/json")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("Failed request: %v", err)
+	}
+
+	ended := recorder.Ended("HTTP GET /")
+	if ended == nil {
+		t.Fatal("Expected ended request span")
+	}
+	attributes := make(map[attribute.Key][]string)
+	for _, kv := range ended.Attributes() {
+		if kv.Value.Type() == attribute.STRINGSLICE {
+			attributes[kv.Key] = kv.Value.AsStringSlice()
+		}
+	}
+
+	expected := map[attribute.Key][]string{
+		"http.request.header.x-request-id":  {"42"},
+		"http.request.header.authorization": {polygon.Redacted},
+		"http.request.header.accept":        {"text/html", "application/json"},
+		"http.response.header.x-cache":      {"HIT"},
+		"http.response.header.set-cookie":   {polygon.Redacted},
+	}
+	for key, values := range expected {
+		if !slices.Equal(attributes[key], values) {
+			t.Errorf("Expected %s = %v, got %v", key, values, attributes[key])
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package telemetrytest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.scnd.dev/open/polygon"
+	"go.scnd.dev/open/polygon/package/span"
+	"go.scnd.dev/open/polygon/package/telemetry"
+)
+
+type Recorder struct {
+	Configuration *polygon.Config
+	Telemetry     *telemetry.Telemetry
+	Spans         *tracetest.SpanRecorder
+	Reader        *sdkmetric.ManualReader
+}
+
+func New(config *polygon.Config) (*Recorder, error) {
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spans),
+		sdktrace.WithSampler(telemetry.NewSampler(config.TelemetrySampling)),
+	)
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	meter := meterProvider.Meter("polygon-test")
+	instrument, err := telemetry.NewInstrument(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		Configuration: config,
+		Spans:         spans,
+		Reader:        reader,
+	}
+	r.Telemetry = &telemetry.Telemetry{
+		Polygon:        r,
+		Meter:          meter,
+		MeterProvider:  meterProvider,
+		Tracer:         tracerProvider.Tracer("polygon-test"),
+		TracerProvider: tracerProvider,
+		Instrument:     instrument,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	return r, nil
+}
+
+func (r *Recorder) Config() *polygon.Config {
+	return r.Configuration
+}
+
+func (r *Recorder) Tracer() trace.Tracer {
+	return r.Telemetry.Tracer
+}
+
+func (r *Recorder) TracerMiddleware() fiber.Handler {
+	return r.Telemetry.Middleware()
+}
+
+func (r *Recorder) Instrument() polygon.Instrument {
+	return r.Telemetry.Instrument
+}
+
+func (r *Recorder) Logger() *slog.Logger {
+	return r.Telemetry.Logger
+}
+
+func (r *Recorder) Span(context context.Context, name string, layer string, arguments map[string]any) polygon.Span {
+	return &span.Wrapper{
+		Span: span.NewContext(r, context, name, layer, arguments),
+	}
+}
+
+func (r *Recorder) ForceFlush(context context.Context) error {
+	return r.Telemetry.ForceFlush(context)
+}
+
+func (r *Recorder) Shutdown(context context.Context) error {
+	return r.Telemetry.Shutdown(context)
+}
+
+func (r *Recorder) Ended(name string) sdktrace.ReadOnlySpan {
+	for _, s := range r.Spans.Ended() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) Metric(name string) metricdata.Aggregation {
+	var metrics metricdata.ResourceMetrics
+	if err := r.Reader.Collect(context.Background(), &metrics); err != nil {
+		return nil
+	}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) Sum(name string) []metricdata.DataPoint[int64] {
+	if sum, ok := r.Metric(name).(metricdata.Sum[int64]); ok {
+		return sum.DataPoints
+	}
+	return nil
+}
+
+func (r *Recorder) Histogram(name string) []metricdata.HistogramDataPoint[int64] {
+	if histogram, ok := r.Metric(name).(metricdata.Histogram[int64]); ok {
+		return histogram.DataPoints
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package polygon
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const Redacted = "REDACTED"
+
+var DefaultRedact = []string{
+	"authorization",
+	"cookie",
+	"password",
+	"secret",
+	"token",
+	"api-key",
+	"apikey",
+}
+
+func (r *Config) Redacted(key string) bool {
+	// * delegate to custom hook
+	if r.TelemetryRedact != nil {
+		return r.TelemetryRedact(key)
+	}
+
+	redact := DefaultRedact
+	if r.TelemetryHttp != nil && r.TelemetryHttp.Redact != nil {
+		redact = r.TelemetryHttp.Redact
+	}
+
+	// * match whole key segments
+	segments := RedactSegments(key)
+	for _, item := range redact {
+		if ContainsSegments(segments, RedactSegments(item)) {
+			return true
+		}
+	}
+	return false
+}
+
+func RedactSegments(key string) []string {
+	segments := make([]string, 0)
+	segment := new(strings.Builder)
+	flush := func() {
+		if segment.Len() > 0 {
+			segments = append(segments, segment.String())
+			segment.Reset()
+		}
+	}
+
+	// * split on separators and camel case boundaries
+	previous := rune(0)
+	for _, char := range key {
+		switch {
+		case !unicode.IsLetter(char) && !unicode.IsDigit(char):
+			flush()
+		case unicode.IsUpper(char) && (unicode.IsLower(previous) || unicode.IsDigit(previous)):
+			flush()
+			segment.WriteRune(unicode.ToLower(char))
+		default:
+			segment.WriteRune(unicode.ToLower(char))
+		}
+		previous = char
+	}
+	flush()
+
+	return segments
+}
+
+func ContainsSegments(segments []string, item []string) bool {
+	if len(item) == 0 {
+		return false
+	}
+	for i := 0; i+len(item) <= len(segments); i++ {
+		if slices.Equal(segments[i:i+len(item)], item) {
+			return true
+		}
+	}
+	return false
+}
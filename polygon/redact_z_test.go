@@ -0,0 +1,45 @@
+package polygon_test
+
+import (
+	"testing"
+
+	"go.scnd.dev/open/polygon"
+)
+
+func TestRedacted(t *testing.T) {
+	config := new(polygon.Config)
+	cases := []struct {
+		key      string
+		expected bool
+	}{
+		{"Authorization", true},
+		{"Set-Cookie", true},
+		{"password", true},
+		{"userPassword", true},
+		{"password_hash", true},
+		{"client_secret", true},
+		{"access_token", true},
+		{"accessToken", true},
+		{"X-Api-Key", true},
+		{"apiKey", true},
+		{"x-apikey", true},
+		{"author", false},
+		{"tokenizer_version", false},
+		{"cookies_enabled", false},
+		{"secretary", false},
+		{"passwords", false},
+		{"api", false},
+		{"user_id", false},
+	}
+	for _, c := range cases {
+		if redacted := config.Redacted(c.key); redacted != c.expected {
+			t.Errorf("Expected %s redacted %v, got %v", c.key, c.expected, redacted)
+		}
+	}
+
+	// Custom lists match the same way
+	config.TelemetryHttp = &polygon.TelemetryHttp{Redact: []string{"session-id"}}
+	if !config.Redacted("X-Session-Id") || config.Redacted("session") || config.Redacted("password") {
+		t.Error("Expected custom redact list to replace the default list")
+	}
+}